@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"photographer/internal/repository"
 	"photographer/internal/service"
 	http_handler "photographer/internal/transport/http"
+	_ "time/tzdata" // часовые пояса фотографов не зависят от tzdata в образе
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -19,6 +21,13 @@ import (
 	_ "photographer/docs" // импорт сгенерированных документов
 )
 
+// @title Photographer API
+// @description Сервис для ведения клиентской базы и доходов фотографа.
+// @BasePath /api/v2
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Токен доступа в формате "Bearer <token>", выдаётся в POST /api/v2/auth/login
 func main() {
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -44,7 +53,18 @@ func main() {
 	}
 
 	repo := repository.New(db)
-	_service := service.New(repo)
+	_service := service.New(repo, service.Options{
+		IdempotencyTTL:   cfg.IdempotencyConfig.TTL,
+		AuthTokenTTL:     cfg.AuthConfig.TokenTTL,
+		ClientRetention:  cfg.ClientConfig.Retention,
+		AnonymizeClients: cfg.ClientConfig.Anonymize,
+	})
+	go _service.PurgeIdempotencyKeys(context.Background(), cfg.IdempotencyConfig.PurgeInterval)
+	go _service.PurgeAuthTokens(context.Background(), cfg.AuthConfig.PurgeInterval)
+	if cfg.ClientConfig.Retention > 0 {
+		go _service.PurgeDeletedClients(context.Background(), cfg.ClientConfig.PurgeInterval)
+	}
+
 	router := http_handler.NewHandler(_service)
 
 	// Добавляем маршрут для Swagger UI
@@ -15,7 +15,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/clients": {
+        "/auth/login": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -24,131 +24,127 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Clients"
+                    "Auth"
                 ],
-                "summary": "Создаёт нового клиента",
+                "summary": "Выдаёт токен доступа по логину и паролю фотографа",
                 "parameters": [
                     {
-                        "description": "Payload для создания клиента",
+                        "description": "Логин и пароль",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http_handler.CreateClientRequest"
+                            "$ref": "#/definitions/http_handler.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ID созданного клиента",
+                        "description": "Токен доступа",
                         "schema": {
-                            "$ref": "#/definitions/http_handler.CreateClientResponse"
+                            "$ref": "#/definitions/domain.AuthToken"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный логин или пароль",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/clients/{id}": {
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Clients"
-                ],
-                "summary": "Обновляет данные клиента",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID клиента",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
+        "/auth/logout": {
+            "post": {
+                "security": [
                     {
-                        "description": "Payload для обновления клиента",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http_handler.UpdateClientRequest"
-                        }
+                        "BearerAuth": []
                     }
                 ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Отзывает текущий токен доступа",
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "204": {
+                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
-            },
-            "delete": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/calendar/{token}.ics": {
+            "get": {
+                "description": "Предстоящие съёмки и съёмки за последние 90 дней с клиентом, местом и долгом\nклиента в описании. Отменённые съёмки отдаются со статусом CANCELLED.",
                 "produces": [
-                    "application/json"
+                    "text/calendar"
                 ],
                 "tags": [
-                    "Clients"
+                    "Calendar"
                 ],
-                "summary": "Удаляет клиента по ID",
+                "summary": "Возвращает ленту съёмок в формате iCalendar",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID клиента",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Токен ленты календаря",
+                        "name": "token",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Лента не найдена или отключена",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/clients/{photographerID}": {
+        "/photographers": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только ID и имя для клиентов. Реквизиты и контакты фотографа отдаёт его профиль\nGET /photographers/{pid}, доступный только ему самому.",
                 "consumes": [
                     "application/json"
                 ],
@@ -156,44 +152,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Clients"
+                    "Photographers"
                 ],
-                "summary": "Возвращает список клиентов фотографа",
+                "summary": "Возвращает список фотографов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID фотографа",
-                        "name": "photographerID",
-                        "in": "path",
-                        "required": true
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Сортировка: name (по имени для клиентов), date; '-' в начале — по убыванию",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрированы не раньше даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрированы не позже даты (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Client"
-                            }
+                            "$ref": "#/definitions/http_handler.GetPhotographersResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/debt": {
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -202,87 +229,117 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Financial"
+                    "Photographers"
                 ],
-                "summary": "Добавляет задолженность для клиента фотографа",
+                "summary": "Создаёт нового фотографа",
                 "parameters": [
                     {
-                        "description": "Payload для добавления задолженности",
+                        "description": "Payload для создания фотографа",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http_handler.AddDebtRequest"
+                            "$ref": "#/definitions/http_handler.CreatePhotographerRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "ID созданного фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CreatePhotographerResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/debtors/{photographerID}": {
+        "/photographers/{pid}": {
             "get": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Financial"
+                    "Photographers"
                 ],
-                "summary": "Получает список должников фотографа",
+                "summary": "Возвращает профиль фотографа",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "ID фотографа",
-                        "name": "photographerID",
+                        "name": "pid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список задолженностей",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Debt"
-                            }
+                            "$ref": "#/definitions/domain.Photographer"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Фотограф не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/incomes/{photographerID}": {
-            "get": {
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняются только переданные поля. Валюту можно сменить, пока у фотографа нет ни одной суммы:\nначислений, оплат, счетов, съёмок и пакетов.",
                 "consumes": [
                     "application/json"
                 ],
@@ -290,323 +347,5626 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Financial"
+                    "Photographers"
                 ],
-                "summary": "Получает детализированный список доходов фотографа",
+                "summary": "Обновляет профиль фотографа",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "ID фотографа",
-                        "name": "photographerID",
+                        "name": "pid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля профиля",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.UpdatePhotographerRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список платежей и общий доход",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/http_handler.GetIncomesResponse"
+                            "$ref": "#/definitions/domain.Photographer"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа или фотограф деактивирован",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Фотограф не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Валюту нельзя сменить: уже есть суммы",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/payment": {
+        "/photographers/{pid}/calendar/token": {
             "post": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Ссылка содержит секретный токен и открывается без аутентификации, поэтому\nеё можно добавить в Google Calendar или Apple Calendar как подписку.\nТокен показывается только в этом ответе, прежняя ссылка перестаёт работать.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Financial"
+                    "Calendar"
                 ],
-                "summary": "Добавляет оплату клиента фотографу",
+                "summary": "Выдаёт новую ссылку на ленту съёмок для календаря",
                 "parameters": [
                     {
-                        "description": "Payload для добавления оплаты",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http_handler.AddPaymentRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CalendarTokenResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Требуется аутентификация",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
-                    }
-                }
-            }
-        },
-        "/photographers": {
-            "get": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Photographers"
-                ],
-                "summary": "Возвращает список фотографов",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Photographer"
-                            }
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
             },
-            "post": {
-                "consumes": [
-                    "application/json"
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Photographers"
+                    "Calendar"
                 ],
-                "summary": "Создаёт нового фотографа",
+                "summary": "Отключает ленту съёмок для календаря",
                 "parameters": [
                     {
-                        "description": "Payload для создания фотографа",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http_handler.CreatePhotographerRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ID созданного фотографа",
-                        "schema": {
-                            "$ref": "#/definitions/http_handler.CreatePhotographerResponse"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
                         }
                     }
                 }
             }
-        }
-    },
+        },
+        "/photographers/{pid}/client-fields": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Возвращает свои поля карточки клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetClientFieldsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Значения поля передаются в custom_fields клиента по ключу key: строки для text,\nselect и date (YYYY-MM-DD), числа для number.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Заводит своё поле карточки клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Поле карточки клиента",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ClientFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CreateClientFieldResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Поле с таким ключом уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/client-fields/{fid}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ключ и тип поля не меняются. Значения клиентов, которых нет среди новых\nвариантов, сохраняются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Меняет подпись и варианты своего поля карточки клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID поля",
+                        "name": "fid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Подпись и варианты поля",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ClientFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Поле не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Значения поля удаляются из карточек всех клиентов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Удаляет своё поле карточки клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID поля",
+                        "name": "fid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Поле не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Возвращает список клиентов фотографа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Сортировка: name, amount, date; '-' в начале — по убыванию",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Созданы не раньше даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Созданы не позже даты (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Задолженность не меньше суммы в минимальных единицах валюты",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Показывать удалённых клиентов",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetClientsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Создаёт нового клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload для создания клиента",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CreateClientRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID созданного клиента",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CreateClientResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Фотограф не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пары клиентов с общим телефоном, общим email или похожими именами, самые вероятные\nпервыми. Телефоны сравниваются по последним десяти цифрам.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Возвращает возможные дубли клиентов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Число пар, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetDuplicateClientsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ищет неудалённых клиентов по имени, контактам и заметкам с учётом опечаток. Запрос из\nцифр ищет и по телефонам, записанным в любом формате. Лучшие совпадения идут первыми,\nу каждого — совпавшие поля, где найденные слова обёрнуты в \u003cmark\u003e.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Ищет клиентов фотографа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Строка поиска",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Число результатов, по умолчанию 20, не больше 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.SearchClientsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалённые клиенты, которых ещё можно восстановить. По истечении срока хранения клиенты\nудаляются окончательно или обезличиваются и из корзины пропадают.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Возвращает корзину удалённых клиентов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-deleted",
+                        "description": "Сортировка: name, amount, date, deleted; '-' в начале — по убыванию",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Удалены не раньше даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Удалены не позже даты (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Задолженность не меньше суммы в минимальных единицах валюты",
+                        "name": "min_amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetClientsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Возвращает клиента фотографа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Client"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняются только переданные поля карточки, остальные остаются прежними.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Обновляет данные клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload для обновления клиента",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.UpdateClientRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Удаляет клиента по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняются только переданные поля карточки, остальные остаются прежними.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Обновляет данные клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload для обновления клиента",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.UpdateClientRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Возвращает баланс клиента: задолженность или кредит",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Баланс клиента",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Balance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/debts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Возвращает историю начислений клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Журнал начислений",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.DebtEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "С package_id сумма считается по прайс-листу: цена пакета, умноженная на quantity,\nплюс выбранные опции пакета. Начисление запоминает пакет, а в lines — количество,\nопции и цены на момент начисления.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Добавляет начисление в журнал задолженностей клиента",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload для добавления задолженности",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.AddDebtRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности для безопасного повтора запроса",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID созданного начисления и баланс клиента",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.AddDebtResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Запрос с этим ключом ещё выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации или ключ уже использован с другим телом запроса",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/invoices": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Номер счёта и начисление клиенту появляются только при выставлении счёта.\nПозиция с debt_id выставляет уже проведённое начисление клиента, например за\nсъёмку или пакет, и повторно его не начисляет.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Создаёт черновик счёта клиенту",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Позиции и срок оплаты счёта",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.InvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CreateInvoiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Начисления, оплаты, съёмки и счета клиента source_id переходят к клиенту из пути в одной\nтранзакции. Карточка дополняется контактами, датой рождения, источником, заметками и\nполями дубля, имя не меняется. Дубль удаляется, объединение попадает в историю клиента.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Объединяет клиента с его дублем",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента, который остаётся",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Дубль, который поглощается",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.MergeClientsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.MergeClientsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Клиент удалён или изменён во время объединения",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/merges": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Возвращает историю объединений клиента с дублями",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetClientMergesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/payments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Добавляет оплату клиента фотографу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payload для добавления оплаты",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.AddPaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности для безопасного повтора запроса",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Баланс клиента после оплаты",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.AddPaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Запрос с этим ключом ещё выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации или ключ уже использован с другим телом запроса",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Клиент возвращается в список вместе с начислениями, оплатами, съёмками и счетами.\nКлиента, поглощённого при объединении, восстановить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Восстанавливает клиента из корзины",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиента нет в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Клиент объединён с другим",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/sessions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Съёмка не может пересекаться по времени с другой неотменённой съёмкой\nфотографа. Подтверждённая при создании съёмка сразу начисляется клиенту.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Бронирует съёмку клиенту",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Время, место и цена съёмки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.SessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CreateSessionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Время занято другой съёмкой",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/sessions/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Каждое событие файла становится предварительной съёмкой клиента без цены:\nSUMMARY — тип, LOCATION — место, DESCRIPTION — примечание. Отменённые события\nпропускаются, повторяющиеся не поддерживаются. Время без зоны считается временем\nфотографа. Съёмки добавляются все вместе или ни одна; номера событий в ошибках\nсчитаются с нуля среди неотменённых событий файла.",
+                "consumes": [
+                    "text/calendar"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Импортирует съёмки клиента из файла iCalendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Файл .ics",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ImportSessionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Съёмка пересекается с другой",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/clients/{cid}/statement": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Входящий баланс, все начисления и оплаты за период по порядку с балансом\nпосле каждой операции и исходящий баланс. С format=html — версия для печати.",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Возвращает выписку по клиенту за период",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "cid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD), по умолчанию сегодня",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "html"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Statement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Клиент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/deactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Для фотографа, который уходит из студии. Клиенты, суммы и отчёты сохраняются и доступны\nна чтение, но любые изменения отклоняются с 403, а войти заново фотограф не может.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Photographers"
+                ],
+                "summary": "Деактивирует фотографа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа или фотограф деактивирован",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Фотограф не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/debtors": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Получает список должников фотографа и клиентов с переплатой",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-amount",
+                        "description": "Сортировка: name, amount, date; '-' в начале — по убыванию",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последняя операция не раньше даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Последняя операция не позже даты (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Задолженность не меньше суммы в минимальных единицах валюты",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Показывать удалённых клиентов",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список задолженностей и кредитов",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetDebtorsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/debtors/aging": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оплаты гасят начисления клиента начиная с самого старого, остаток\nраскладывается по корзинам 0–30, 31–60, 61–90 и более 90 дней.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Возвращает задолженность клиентов по давности начислений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата отчёта (YYYY-MM-DD), по умолчанию сегодня",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Учитывать удалённых клиентов",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AgingReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/incomes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Получает детализированный список доходов фотографа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-date",
+                        "description": "Сортировка: amount, date; '-' в начале — по убыванию",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Оплаты не раньше даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Оплаты не позже даты (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID клиента",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Движения по оплатам не меньше суммы по модулю в минимальных единицах валюты",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Учитывать оплаты удалённых клиентов",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month",
+                            "year"
+                        ],
+                        "type": "string",
+                        "description": "Отчёт по интервалам с разбивкой по клиентам и сравнением с предыдущим периодом",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список платежей, общий доход и доход по способам оплаты",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetIncomesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Возвращает счета фотографа без позиций",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "sent",
+                            "paid",
+                            "void"
+                        ],
+                        "type": "string",
+                        "description": "Статус счёта",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-date",
+                        "description": "Сортировка: amount, date; '-' в начале — по убыванию",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Созданы не раньше даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Созданы не позже даты (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только счета клиента",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сумма счёта не меньше суммы в минимальных единицах валюты",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Показывать счета удалённых клиентов",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetInvoicesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/invoices/{iid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Возвращает счёт с позициями",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID счёта",
+                        "name": "iid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Счёт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Заменяет содержимое черновика счёта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID счёта",
+                        "name": "iid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Позиции и срок оплаты счёта",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.InvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Счёт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Счёт уже выставлен",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/invoices/{iid}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Черновик печатается без номера с пометкой «Черновик».",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Возвращает счёт в PDF",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID счёта",
+                        "name": "iid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Счёт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/invoices/{iid}/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Черновик можно выставить (sent) или аннулировать (void), выставленный и ещё\nне оплаченный счёт — аннулировать. При выставлении счёт получает следующий номер\nфотографа за год, а позиции без debt_id начисляются клиенту; аннулирование\nсторнирует эти начисления, уже проведённые до счёта начисления остаются.\nСтатус paid вручную не задаётся: счёт оплачен, когда оплаты клиента покрывают\nвсе его начисления вместе с более ранними.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Меняет статус счёта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID счёта",
+                        "name": "iid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ChangeInvoiceStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Счёт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Переход из текущего статуса невозможен",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/packages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packages"
+                ],
+                "summary": "Возвращает прайс-лист фотографа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Показывать пакеты из архива",
+                        "name": "include_archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetPackagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packages"
+                ],
+                "summary": "Добавляет пакет услуг в прайс-лист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Пакет услуг с опциями",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.PackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.CreatePackageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Пакет с таким названием уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/packages/{pkgid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packages"
+                ],
+                "summary": "Возвращает пакет услуг с опциями",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пакета",
+                        "name": "pkgid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Package"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Пакет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Опции получают новые ID. Начисления, уже сделанные по пакету, не меняются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packages"
+                ],
+                "summary": "Заменяет пакет услуг вместе с опциями",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пакета",
+                        "name": "pkgid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Пакет услуг с опциями",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.PackageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Пакет не найден или в архиве",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Пакет с таким названием уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Пакет пропадает из прайс-листа, но остаётся у сделанных по нему начислений.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Packages"
+                ],
+                "summary": "Убирает пакет услуг в архив",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пакета",
+                        "name": "pkgid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Пакет не найден или уже в архиве",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/payments/{payid}/refunds": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возврат уменьшает доход датой проведения и увеличивает задолженность\nклиента. Сумма возвратов по оплате не может превысить саму оплату.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Проводит возврат по оплате",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID оплаты",
+                        "name": "payid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Сумма и причина возврата",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.RefundPaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности для безопасного повтора запроса",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID возврата и баланс клиента",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.RefundPaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Оплата не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Оплата отменена или не является оплатой",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/payments/{payid}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Исходная операция остаётся в истории с отметкой voided_at, а сторно той же\nдатой возвращает баланс клиента и доходы к состоянию без неё. Оплату с\nдействующими возвратами отменить нельзя, сначала отменяются возвраты.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Financial"
+                ],
+                "summary": "Отменяет ошибочную оплату или возврат",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID оплаты или возврата",
+                        "name": "payid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отмены",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.VoidPaymentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности для безопасного повтора запроса",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Баланс клиента после отмены",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.AddPaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Оплата не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Оплата уже отменена или по ней есть возвраты",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Возвращает съёмки фотографа",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "tentative",
+                            "confirmed",
+                            "completed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Статус съёмки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, по умолчанию 50, не больше 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "date",
+                        "description": "Сортировка: amount, date; '-' в начале — по убыванию",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начинаются не раньше даты (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начинаются не позже даты (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только съёмки клиента",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Цена не меньше суммы в минимальных единицах валюты",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Показывать съёмки удалённых клиентов",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.GetSessionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/sessions/{sid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Возвращает съёмку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID съёмки",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Session"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Съёмка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Статус в теле запроса не учитывается. Проведённую и отменённую съёмку\nменять нельзя; у подтверждённой изменение цены проводится корректировкой начисления.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Меняет время, место, тип, цену и примечание съёмки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID съёмки",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Время, место и цена съёмки",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.SessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Съёмка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Съёмка завершена или время занято",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подтверждённую или проведённую съёмку удалить нельзя, её можно только отменить.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Удаляет ошибочно заведённую съёмку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID съёмки",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Съёмка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Съёмка уже начислена клиенту",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/photographers/{pid}/sessions/{sid}/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Предварительную съёмку можно подтвердить (confirmed) или отменить (cancelled),\nподтверждённую — провести (completed) или отменить. Подтверждение начисляет\nцену съёмки клиенту, отмена подтверждённой съёмки сторнирует начисление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Меняет статус съёмки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID фотографа",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID съёмки",
+                        "name": "sid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ChangeSessionStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется аутентификация",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Нет доступа к данным другого фотографа",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Съёмка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Переход из текущего статуса невозможен",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http_handler.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
     "definitions": {
+        "domain.Addon": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/domain.Money"
+                }
+            }
+        },
+        "domain.AgingReport": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "clients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AgingTotal"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/domain.AgingTotal"
+                }
+            }
+        },
+        "domain.AgingTotal": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "days_0_30": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "days_31_60": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "days_61_90": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "days_over_90": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "total": {
+                    "$ref": "#/definitions/domain.Money"
+                }
+            }
+        },
+        "domain.AuthToken": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Balance": {
+            "type": "object",
+            "properties": {
+                "credit": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "debt": {
+                    "$ref": "#/definitions/domain.Money"
+                }
+            }
+        },
         "domain.Client": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "balance": {
+                    "$ref": "#/definitions/domain.Balance"
+                },
+                "birthday": {
+                    "type": "string"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Contact"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ClientField": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ClientMatch": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "$ref": "#/definitions/domain.Client"
+                },
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Highlight"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.ClientMerge": {
+            "type": "object",
+            "properties": {
+                "debts": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoices": {
+                    "type": "integer"
+                },
+                "merged_at": {
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "integer"
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "sessions": {
+                    "type": "integer"
+                },
+                "source_id": {
+                    "type": "integer"
+                },
+                "source_name": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Contact": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Debt": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "credit": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "occurredAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DebtEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DebtLine"
+                    }
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "package_id": {
+                    "type": "integer"
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "reversal_of": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DebtLine": {
+            "type": "object",
+            "properties": {
+                "addon_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/domain.Money"
+                }
+            }
+        },
+        "domain.DuplicateCandidate": {
+            "type": "object",
+            "properties": {
+                "client": {
+                    "$ref": "#/definitions/domain.Client"
+                },
+                "duplicate": {
+                    "$ref": "#/definitions/domain.Client"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "amount"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be positive"
+                }
+            }
+        },
+        "domain.Highlight": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "marked": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IncomeBucket": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IncomeReport": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IncomeBucket"
+                    }
+                },
+                "clients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.IncomeTotal"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/domain.IncomeTotal"
+                }
+            }
+        },
+        "domain.IncomeTotal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "previous_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Invoice": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issue_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.InvoiceItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.InvoiceItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "debt_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/domain.Money"
+                }
+            }
+        },
+        "domain.MethodTotal": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 150050
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                }
+            }
+        },
+        "domain.Package": {
+            "type": "object",
+            "properties": {
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Addon"
+                    }
+                },
+                "archived_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deliverables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "occurredAt": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "reversal_of": {
+                    "type": "integer"
+                },
+                "voided_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Photographer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "deactivated_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "legal_name": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "tax_id": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PhotographerSummary": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Session": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "photographer_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Statement": {
+            "type": "object",
+            "properties": {
+                "charged": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "closing": {
+                    "$ref": "#/definitions/domain.Balance"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StatementEntry"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "opening": {
+                    "$ref": "#/definitions/domain.Balance"
+                },
+                "paid": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StatementEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/domain.Money"
+                },
+                "balance": {
+                    "$ref": "#/definitions/domain.Balance"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "reversal_of": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "http_handler.AddDebtRequest": {
+            "type": "object",
+            "properties": {
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_handler.ChargeAddonRequest"
+                    }
+                },
+                "amount": {
+                    "type": "integer",
+                    "example": 150050
+                },
+                "client_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Свадебная съёмка"
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2025-03-01"
+                },
+                "package_id": {
+                    "description": "PackageID начисляет пакет из прайс-листа: сумма считается по ценам\nпакета и опций, Amount и Currency не передаются, Description необязателен.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "photographer_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.AddDebtResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/domain.Balance"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.AddPaymentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 50000
+                },
+                "client_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "card",
+                        "bank_transfer",
+                        "sbp",
+                        "other"
+                    ],
+                    "example": "cash"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Получено на съёмке"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2026-02-10T18:30:00+03:00"
+                },
+                "photographer_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "SBP-8841"
+                }
+            }
+        },
+        "http_handler.AddPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/domain.Balance"
+                }
+            }
+        },
+        "http_handler.AddonRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Дополнительный час"
+                },
+                "price": {
+                    "type": "integer",
+                    "example": 800000
+                }
+            }
+        },
+        "http_handler.CalendarTokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "mN3s8Qv1xYc2ZbT7kLw0pR5uE9aH4dJ6fG1iK8oV2sM"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://api.example.com/api/v2/calendar/mN3s8Qv1xYc2ZbT7kLw0pR5uE9aH4dJ6fG1iK8oV2sM.ics"
+                }
+            }
+        },
+        "http_handler.ChangeInvoiceStatusRequest": {
+            "type": "object",
+            "properties": {
+                "issue_date": {
+                    "type": "string",
+                    "example": "2026-02-15"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "sent"
+                }
+            }
+        },
+        "http_handler.ChangeSessionStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "completed",
+                        "cancelled"
+                    ],
+                    "example": "confirmed"
+                }
+            }
+        },
+        "http_handler.ChargeAddonRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "http_handler.ClientFieldRequest": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "shoot_style"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Стиль съёмки"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "репортаж",
+                        "постановка"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "number",
+                        "date",
+                        "select"
+                    ],
+                    "example": "select"
+                }
+            }
+        },
+        "http_handler.CreateClientFieldResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.CreateClientRequest": {
+            "type": "object",
+            "properties": {
+                "birthday": {
+                    "type": "string",
+                    "example": "1990-05-01"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Contact"
+                    }
+                },
+                "custom_fields": {
+                    "type": "object"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Alice"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Предпочитает съёмку на закате"
+                },
+                "photographer_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "source": {
+                    "type": "string",
+                    "example": "Instagram"
+                }
+            }
+        },
+        "http_handler.CreateClientResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.CreateInvoiceResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.CreatePackageResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.CreatePhotographerRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "login": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Alice"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "s3cret-pass"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Asia/Yekaterinburg"
+                }
+            }
+        },
+        "http_handler.CreatePhotographerResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.CreateSessionResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "http_handler.GetClientFieldsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ClientField"
+                    }
+                }
+            }
+        },
+        "http_handler.GetClientMergesResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ClientMerge"
+                    }
+                }
+            }
+        },
+        "http_handler.GetClientsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Client"
+                    }
+                },
+                "next_cursor": {
                     "type": "string"
+                }
+            }
+        },
+        "http_handler.GetDebtorsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Debt"
+                    }
                 },
-                "deleted_at": {
+                "next_cursor": {
                     "type": "string"
+                }
+            }
+        },
+        "http_handler.GetDuplicateClientsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DuplicateCandidate"
+                    }
+                }
+            }
+        },
+        "http_handler.GetIncomesResponse": {
+            "type": "object",
+            "properties": {
+                "by_method": {
+                    "description": "ByMethod — та же сумма с разбивкой по способам оплаты.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.MethodTotal"
+                    }
                 },
-                "id": {
-                    "type": "integer"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Payment"
+                    }
                 },
-                "name": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "photographer_id": {
-                    "type": "integer"
+                "report": {
+                    "description": "Report есть в ответе, только если запрошен group_by.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.IncomeReport"
+                        }
+                    ]
                 },
-                "updated_at": {
-                    "type": "string"
+                "total": {
+                    "$ref": "#/definitions/domain.Money"
                 }
             }
         },
-        "domain.Debt": {
+        "http_handler.GetInvoicesResponse": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "integer"
-                },
-                "client_id": {
-                    "type": "integer"
-                },
-                "client_name": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Invoice"
+                    }
                 },
-                "occurredAt": {
+                "next_cursor": {
                     "type": "string"
                 }
             }
         },
-        "domain.Payment": {
+        "http_handler.GetPackagesResponse": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "integer"
-                },
-                "client_id": {
-                    "type": "integer"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Package"
+                    }
+                }
+            }
+        },
+        "http_handler.GetPhotographersResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PhotographerSummary"
+                    }
                 },
-                "occurredAt": {
+                "next_cursor": {
                     "type": "string"
                 }
             }
         },
-        "domain.Photographer": {
+        "http_handler.GetSessionsResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Session"
+                    }
                 },
-                "name": {
+                "next_cursor": {
                     "type": "string"
                 }
             }
         },
-        "http_handler.AddDebtRequest": {
+        "http_handler.ImportSessionsResponse": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "integer",
-                    "example": 500
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "http_handler.InvoiceItemRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
                 },
-                "client_id": {
+                "debt_id": {
                     "type": "integer",
-                    "example": 2
+                    "example": 12
                 },
-                "photographer_id": {
+                "description": {
+                    "type": "string",
+                    "example": "Свадебная съёмка, 8 часов"
+                },
+                "quantity": {
                     "type": "integer",
                     "example": 1
+                },
+                "unit_price": {
+                    "type": "integer",
+                    "example": 4000000
                 }
             }
         },
-        "http_handler.AddPaymentRequest": {
+        "http_handler.InvoiceRequest": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "integer",
-                    "example": 500
+                "due_date": {
+                    "type": "string",
+                    "example": "2026-03-01"
                 },
-                "client_id": {
-                    "type": "integer",
-                    "example": 2
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_handler.InvoiceItemRequest"
+                    }
                 },
-                "photographer_id": {
+                "note": {
+                    "type": "string",
+                    "example": "Оплата переводом по реквизитам"
+                }
+            }
+        },
+        "http_handler.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "s3cret-pass"
+                }
+            }
+        },
+        "http_handler.MergeClientsRequest": {
+            "type": "object",
+            "properties": {
+                "source_id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 7
                 }
             }
         },
-        "http_handler.CreateClientRequest": {
+        "http_handler.MergeClientsResponse": {
             "type": "object",
             "properties": {
+                "balance": {
+                    "$ref": "#/definitions/domain.Balance"
+                },
+                "merge": {
+                    "$ref": "#/definitions/domain.ClientMerge"
+                }
+            }
+        },
+        "http_handler.PackageRequest": {
+            "type": "object",
+            "properties": {
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http_handler.AddonRequest"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "deliverables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "150 фото в обработке",
+                        "онлайн-галерея"
+                    ]
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Репортажная съёмка до 3 часов"
+                },
+                "duration_minutes": {
+                    "type": "integer",
+                    "example": 180
+                },
                 "name": {
                     "type": "string",
-                    "example": "Alice"
+                    "example": "Стандарт"
                 },
-                "photographer_id": {
+                "price": {
                     "type": "integer",
-                    "example": 1
+                    "example": 3000000
                 }
             }
         },
-        "http_handler.CreateClientResponse": {
+        "http_handler.ProblemDetails": {
             "type": "object",
             "properties": {
-                "id": {
+                "detail": {
+                    "type": "string",
+                    "example": "client 1 not found"
+                },
+                "errors": {
+                    "description": "Errors перечисляет ошибки по полям запроса для ответов 422.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/clients/1"
+                },
+                "status": {
                     "type": "integer",
-                    "example": 1
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
                 }
             }
         },
-        "http_handler.CreatePhotographerRequest": {
+        "http_handler.RefundPaymentRequest": {
             "type": "object",
             "properties": {
-                "name": {
+                "amount": {
+                    "type": "integer",
+                    "example": 50000
+                },
+                "currency": {
                     "type": "string",
-                    "example": "Alice"
+                    "example": "RUB"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Отказ от ретуши"
                 }
             }
         },
-        "http_handler.CreatePhotographerResponse": {
+        "http_handler.RefundPaymentResponse": {
             "type": "object",
             "properties": {
+                "balance": {
+                    "$ref": "#/definitions/domain.Balance"
+                },
                 "id": {
                     "type": "integer",
-                    "example": 1
+                    "example": 12
                 }
             }
         },
-        "http_handler.GetIncomesResponse": {
+        "http_handler.SearchClientsResponse": {
             "type": "object",
             "properties": {
-                "payments": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.Payment"
+                        "$ref": "#/definitions/domain.ClientMatch"
                     }
+                }
+            }
+        },
+        "http_handler.SessionRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
                 },
-                "total": {
+                "ends_at": {
+                    "type": "string",
+                    "example": "2026-06-13T18:00:00+03:00"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Парк Горького"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Взять второй объектив"
+                },
+                "price": {
                     "type": "integer",
-                    "example": 10000
+                    "example": 4000000
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2026-06-13T14:00:00+03:00"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "tentative",
+                        "confirmed"
+                    ],
+                    "example": "tentative"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Свадебная"
                 }
             }
         },
         "http_handler.UpdateClientRequest": {
             "type": "object",
             "properties": {
+                "birthday": {
+                    "type": "string",
+                    "example": "1990-05-01"
+                },
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Contact"
+                    }
+                },
+                "custom_fields": {
+                    "type": "object"
+                },
                 "name": {
                     "type": "string",
                     "example": "Alice Updated"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "Рекомендация"
+                }
+            }
+        },
+        "http_handler.UpdatePhotographerRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Alice Photo"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "alice@example.com"
+                },
+                "legal_name": {
+                    "type": "string",
+                    "example": "ИП Иванова Алиса Сергеевна"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Alice"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+7 900 123-45-67"
+                },
+                "tax_id": {
+                    "type": "string",
+                    "example": "770123456789"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Asia/Yekaterinburg"
                 }
             }
+        },
+        "http_handler.VoidPaymentRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Оплата внесена дважды"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Токен доступа в формате \"Bearer \u003ctoken\u003e\", выдаётся в POST /api/v2/auth/login",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -615,10 +5975,10 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "",
 	Host:             "",
-	BasePath:         "",
+	BasePath:         "/api/v2",
 	Schemes:          []string{},
-	Title:            "",
-	Description:      "",
+	Title:            "Photographer API",
+	Description:      "Сервис для ведения клиентской базы и доходов фотографа.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
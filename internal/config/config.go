@@ -1,14 +1,20 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	PostgresConfig PostgresConfig
+	PostgresConfig    PostgresConfig
+	IdempotencyConfig IdempotencyConfig
+	AuthConfig        AuthConfig
+	ClientConfig      ClientConfig
 }
 
 type PostgresConfig struct {
@@ -20,11 +26,64 @@ type PostgresConfig struct {
 	SSLMode  string
 }
 
+type IdempotencyConfig struct {
+	TTL           time.Duration
+	PurgeInterval time.Duration
+}
+
+type AuthConfig struct {
+	TokenTTL      time.Duration
+	PurgeInterval time.Duration
+}
+
+// ClientConfig задаёт срок хранения удалённых клиентов в корзине. Нулевой
+// Retention отключает очистку корзины.
+type ClientConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+	Anonymize     bool
+}
+
 func LoadConfig() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println(".env file not found, using environment variables")
 	}
 
+	idempotencyTTL, err := getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyPurgeInterval, err := getEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	authTokenTTL, err := getEnvDuration("AUTH_TOKEN_TTL", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	authPurgeInterval, err := getEnvDuration("AUTH_TOKEN_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	clientRetentionDays, err := getEnvInt("CLIENT_RETENTION_DAYS", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	clientPurgeInterval, err := getEnvDuration("CLIENT_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	clientPurgeMode := getEnv("CLIENT_PURGE_MODE", "anonymize")
+	if clientPurgeMode != "anonymize" && clientPurgeMode != "delete" {
+		return nil, fmt.Errorf("invalid CLIENT_PURGE_MODE: must be anonymize or delete")
+	}
+
 	config := &Config{
 		PostgresConfig: PostgresConfig{
 			Host:     getEnv("POSTGRES_HOST", "localhost"),
@@ -34,6 +93,19 @@ func LoadConfig() (*Config, error) {
 			DBName:   getEnv("POSTGRES_DB", "dbname"),
 			SSLMode:  getEnv("POSTGRES_SSLMODE", "disable"),
 		},
+		IdempotencyConfig: IdempotencyConfig{
+			TTL:           idempotencyTTL,
+			PurgeInterval: idempotencyPurgeInterval,
+		},
+		AuthConfig: AuthConfig{
+			TokenTTL:      authTokenTTL,
+			PurgeInterval: authPurgeInterval,
+		},
+		ClientConfig: ClientConfig{
+			Retention:     time.Duration(clientRetentionDays) * 24 * time.Hour,
+			PurgeInterval: clientPurgeInterval,
+			Anonymize:     clientPurgeMode == "anonymize",
+		},
 	}
 
 	return config, nil
@@ -45,3 +117,37 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be positive", key)
+	}
+
+	return duration, nil
+}
+
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if number < 0 {
+		return 0, fmt.Errorf("invalid %s: must not be negative", key)
+	}
+
+	return number, nil
+}
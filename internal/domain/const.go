@@ -3,4 +3,15 @@ package domain
 type (
 	PhotographerID int64
 	ClientID       int64
+	DebtID         int64
+	PaymentID      int64
+	InvoiceID      int64
+	SessionID      int64
+	PackageID      int64
+	AddonID        int64
+	ClientFieldID  int64
+	ClientMergeID  int64
 )
+
+// DefaultTimeZone — часовой пояс фотографа, если при регистрации он не указан.
+const DefaultTimeZone = "Europe/Moscow"
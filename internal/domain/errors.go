@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Виды доменных ошибок. Транспортный слой сопоставляет их с кодами ответа.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	ErrForbidden  = errors.New("forbidden")
+
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error — доменная ошибка с сообщением, которое безопасно показывать клиенту API.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func NewError(kind error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// FieldError — ошибка валидации отдельного поля запроса.
+type FieldError struct {
+	Field   string `json:"field" example:"amount"`
+	Message string `json:"message" example:"must be positive"`
+}
+
+// ValidationError собирает ошибки валидации по полям запроса.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Add(field, format string, args ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err возвращает nil, если ошибок не набралось.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(ErrValidation.Error())
+	for i, f := range e.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(f.Field + " " + f.Message)
+	}
+	return b.String()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ListParams — параметры постраничной выдачи списков. Фильтры, которые список не
+// поддерживает, игнорируются.
+type ListParams struct {
+	Limit  int
+	Cursor *Cursor
+
+	// Sort — ключ сортировки (name, amount, date), "-" в начале означает по убыванию.
+	Sort string
+
+	// From и To задают полуинтервал [From, To) по дате записи.
+	From *time.Time
+	To   *time.Time
+
+	ClientID       ClientID
+	MinAmount      int64
+	IncludeDeleted bool
+}
+
+// SortKey возвращает ключ сортировки без направления.
+func (p ListParams) SortKey() string {
+	return strings.TrimPrefix(p.Sort, "-")
+}
+
+func (p ListParams) Descending() bool {
+	return strings.HasPrefix(p.Sort, "-")
+}
+
+// Page — страница списка. NextCursor пуст, если записей больше нет.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Cursor указывает на последнюю запись страницы: значение ключа сортировки и ID,
+// по которым выбирается следующая страница.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, NewError(ErrValidation, "invalid cursor")
+	}
+
+	var cursor Cursor
+	if err = json.Unmarshal(raw, &cursor); err != nil {
+		return nil, NewError(ErrValidation, "invalid cursor")
+	}
+
+	return &cursor, nil
+}
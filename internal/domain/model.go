@@ -2,30 +2,519 @@ package domain
 
 import "time"
 
+// Photographer.TimeZone — имя часового пояса IANA. В нём отдаются все метки
+// времени фотографа и считаются отчёты по дням и месяцам. Currency — валюта,
+// в которой ведутся все расчёты фотографа. DisplayName — имя для клиентов,
+// LegalName и TaxID — реквизиты ИП или студии. Деактивированный фотограф
+// (DeactivatedAt задан) видит свою историю, но не может её менять.
 type Photographer struct {
-	ID        PhotographerID `json:"id"`
-	Name      string         `json:"name"`
-	CreatedAt time.Time      `json:"created_at"`
+	ID            PhotographerID `json:"id"`
+	Name          string         `json:"name"`
+	Login         string         `json:"login"`
+	DisplayName   string         `json:"display_name"`
+	LegalName     string         `json:"legal_name"`
+	TaxID         string         `json:"tax_id"`
+	Email         string         `json:"email"`
+	Phone         string         `json:"phone"`
+	TimeZone      string         `json:"time_zone"`
+	Currency      string         `json:"currency"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeactivatedAt *time.Time     `json:"deactivated_at,omitempty"`
 }
 
+// PhotographerSummary — публичные сведения о фотографе для общего списка:
+// DisplayName — имя для клиентов, а если оно не задано — имя фотографа.
+type PhotographerSummary struct {
+	ID          PhotographerID `json:"id"`
+	DisplayName string         `json:"display_name"`
+}
+
+// PhotographerPatch — частичное обновление профиля фотографа: nil-поле не
+// меняется, пустая строка стирает необязательное поле.
+type PhotographerPatch struct {
+	Name        *string
+	DisplayName *string
+	LegalName   *string
+	TaxID       *string
+	Email       *string
+	Phone       *string
+	TimeZone    *string
+	Currency    *string
+}
+
+// AuthToken — выданный фотографу токен доступа. Сам токен хранится только у
+// клиента, в базе лежит его хэш.
+type AuthToken struct {
+	Token          string         `json:"token"`
+	PhotographerID PhotographerID `json:"photographer_id"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	// Deactivated — фотограф деактивирован, токен годится только для чтения.
+	Deactivated bool `json:"-"`
+}
+
+// Client.CustomFields хранит значения полей, заведённых фотографом, по ключу
+// поля: строки для text, select и date (YYYY-MM-DD), числа для number.
 type Client struct {
 	ID             ClientID       `json:"id"`
 	Name           string         `json:"name"`
 	PhotographerID PhotographerID `json:"photographer_id"`
+	Contacts       []Contact      `json:"contacts"`
+	Birthday       *time.Time     `json:"birthday"`
+	Source         string         `json:"source"`
+	Notes          string         `json:"notes"`
+	CustomFields   map[string]any `json:"custom_fields"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      *time.Time     `json:"deleted_at"`
+	Balance        Balance        `json:"balance"`
+}
+
+// Contact — способ связи с клиентом, Label уточняет его, например «рабочий».
+type Contact struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+}
+
+// Виды контактов клиента.
+const (
+	ContactPhone     = "phone"
+	ContactEmail     = "email"
+	ContactInstagram = "instagram"
+	ContactTelegram  = "telegram"
+	ContactWhatsApp  = "whatsapp"
+	ContactVK        = "vk"
+	ContactOther     = "other"
+)
+
+// ClientPatch — частичное обновление клиента: nil-поле не меняется.
+// ClearBirthday стирает дату рождения. В CustomFields меняются только
+// переданные поля, nil-значение стирает поле.
+type ClientPatch struct {
+	Name          *string
+	Contacts      *[]Contact
+	Birthday      *time.Time
+	ClearBirthday bool
+	Source        *string
+	Notes         *string
+	CustomFields  map[string]any
+}
+
+// ClientField — поле карточки клиента, заведённое фотографом. Options —
+// варианты значения для типа select.
+type ClientField struct {
+	ID             ClientFieldID  `json:"id"`
+	PhotographerID PhotographerID `json:"photographer_id"`
+	Key            string         `json:"key"`
+	Label          string         `json:"label"`
+	Type           string         `json:"type"`
+	Options        []string       `json:"options"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// Типы полей клиента.
+const (
+	ClientFieldText   = "text"
+	ClientFieldNumber = "number"
+	ClientFieldDate   = "date"
+	ClientFieldSelect = "select"
+)
+
+// ClientMatch — клиент, найденный поиском. Score — близость к запросу от 0 до 1.
+type ClientMatch struct {
+	Client     Client      `json:"client"`
+	Score      float64     `json:"score"`
+	Highlights []Highlight `json:"highlights"`
+}
+
+// Highlight — поле клиента, совпавшее с запросом. Marked — значение поля,
+// экранированное для HTML, в котором совпавшие слова обёрнуты в <mark>.
+type Highlight struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Marked string `json:"marked"`
+}
+
+// DuplicateCandidate — пара клиентов, похожих на одного человека. Reasons —
+// почему: общий телефон (phone), общий email (email) или похожее имя (name).
+type DuplicateCandidate struct {
+	Client    Client   `json:"client"`
+	Duplicate Client   `json:"duplicate"`
+	Reasons   []string `json:"reasons"`
+	Score     float64  `json:"score"`
+}
+
+// Причины, по которым клиенты считаются возможными дублями.
+const (
+	DuplicatePhone = "phone"
+	DuplicateEmail = "email"
+	DuplicateName  = "name"
+)
+
+// ClientMerge — запись истории объединения: клиент SourceID поглощён клиентом
+// TargetID, счётчики показывают, сколько записей перешло к TargetID.
+type ClientMerge struct {
+	ID             ClientMergeID  `json:"id"`
+	PhotographerID PhotographerID `json:"photographer_id"`
+	TargetID       ClientID       `json:"target_id"`
+	SourceID       ClientID       `json:"source_id"`
+	SourceName     string         `json:"source_name"`
+	Debts          int            `json:"debts"`
+	Payments       int            `json:"payments"`
+	Sessions       int            `json:"sessions"`
+	Invoices       int            `json:"invoices"`
+	MergedAt       time.Time      `json:"merged_at"`
+}
+
+// Balance — состояние расчётов с клиентом. Переплата не теряется, а копится
+// как кредит и погашает следующие начисления.
+type Balance struct {
+	Debt   Money `json:"debt"`
+	Credit Money `json:"credit"`
+}
+
+// NewBalance строит баланс по разнице между начислениями и оплатами.
+func NewBalance(net Money) Balance {
+	zero := NewMoney(0, net.Currency)
+	if net.IsNegative() {
+		return Balance{Debt: zero, Credit: net.Neg()}
+	}
+	return Balance{Debt: net, Credit: zero}
 }
 
 type Debt struct {
 	ClientID   ClientID `json:"client_id"`
 	ClientName string   `json:"client_name"`
-	Amount     int      `json:"amount"`
+	Amount     Money    `json:"amount"`
+	Credit     Money    `json:"credit"`
 	OccurredAt time.Time
 }
 
+// DebtEntry — отдельное начисление в журнале задолженностей клиента. Сторно и
+// корректировка ссылаются на исходное начисление в ReversalOf, их Amount может
+// быть отрицательным. У начисления по пакету Lines — его состав.
+type DebtEntry struct {
+	ID             DebtID         `json:"id"`
+	PhotographerID PhotographerID `json:"photographer_id"`
+	ClientID       ClientID       `json:"client_id"`
+	Kind           string         `json:"kind"`
+	Amount         Money          `json:"amount"`
+	ReversalOf     *DebtID        `json:"reversal_of,omitempty"`
+	Description    string         `json:"description"`
+	DueDate        *time.Time     `json:"due_date"`
+	PackageID      *PackageID     `json:"package_id,omitempty"`
+	Lines          []DebtLine     `json:"lines,omitempty"`
+	OccurredAt     time.Time      `json:"occurred_at"`
+}
+
+// DebtLine — строка начисления по пакету: сам пакет (AddonID пуст) или опция,
+// с количеством и ценой из прайс-листа на момент начисления.
+type DebtLine struct {
+	AddonID   *AddonID `json:"addon_id,omitempty"`
+	Name      string   `json:"name"`
+	Quantity  int      `json:"quantity"`
+	UnitPrice Money    `json:"unit_price"`
+	Amount    Money    `json:"amount"`
+}
+
+// Виды начислений. Проведённое начисление не меняется: сторно (void) снимает его
+// остаток целиком, корректировка (adjustment) меняет его сумму на разницу.
+const (
+	DebtKindCharge     = "charge"
+	DebtKindAdjustment = "adjustment"
+	DebtKindVoid       = "void"
+)
+
+// Payment — движение денег от клиента. Возврат и сторно ссылаются на исходное
+// движение в ReversalOf и идут с обратным знаком, Reason — их причина. VoidedAt
+// заполнен у движения, отменённого сторно.
 type Payment struct {
-	ClientID   ClientID `json:"client_id"`
-	Amount     int      `json:"amount"`
+	ID         PaymentID  `json:"id"`
+	ClientID   ClientID   `json:"client_id"`
+	Kind       string     `json:"kind"`
+	Amount     Money      `json:"amount"`
+	Method     string     `json:"method"`
+	Reference  string     `json:"reference,omitempty"`
+	Note       string     `json:"note,omitempty"`
+	ReversalOf *PaymentID `json:"reversal_of,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	VoidedAt   *time.Time `json:"voided_at,omitempty"`
 	OccurredAt time.Time
 }
+
+// Виды движений по оплатам. Сторно (void) отменяет ошибочную оплату или возврат
+// той же датой, возврат (refund) проводится датой возврата.
+const (
+	PaymentKindPayment = "payment"
+	PaymentKindRefund  = "refund"
+	PaymentKindVoid    = "void"
+)
+
+// Способы оплаты. Возврат и сторно проводятся тем же способом, что и исходная оплата.
+const (
+	PaymentMethodCash         = "cash"
+	PaymentMethodCard         = "card"
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodSBP          = "sbp"
+	PaymentMethodOther        = "other"
+)
+
+// PaymentTotals — сумма движений по оплатам с разбивкой по способам оплаты.
+type PaymentTotals struct {
+	Total    Money         `json:"total"`
+	ByMethod []MethodTotal `json:"by_method"`
+}
+
+// MethodTotal — сумма движений и число оплат одного способа оплаты.
+type MethodTotal struct {
+	Method string `json:"method"`
+	Amount Money  `json:"amount"`
+	Count  int    `json:"count"`
+}
+
+// IdempotencyRecord — результат запроса, сохранённый под ключом идемпотентности.
+// Пока запрос выполняется, StatusCode равен нулю.
+type IdempotencyRecord struct {
+	Key         string
+	Scope       string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// IncomeReport — доходы за период с разбивкой по интервалам и клиентам и
+// сравнением с предыдущим периодом той же длины. Без From отчёт строится за всё
+// время и сравнения не содержит.
+type IncomeReport struct {
+	GroupBy string         `json:"group_by"`
+	From    *time.Time     `json:"from,omitempty"`
+	To      time.Time      `json:"to"`
+	Total   IncomeTotal    `json:"total"`
+	Buckets []IncomeBucket `json:"buckets"`
+	Clients []IncomeTotal  `json:"clients"`
+}
+
+// IncomeTotal — сумма и число оплат за период и за предыдущий период. ChangePercent
+// пуст, если в предыдущем периоде оплат не было.
+type IncomeTotal struct {
+	ClientID      ClientID `json:"client_id,omitempty"`
+	ClientName    string   `json:"client_name,omitempty"`
+	Amount        Money    `json:"amount"`
+	Count         int      `json:"count"`
+	Previous      *Money   `json:"previous,omitempty"`
+	PreviousCount int      `json:"previous_count,omitempty"`
+	ChangePercent *float64 `json:"change_percent,omitempty"`
+}
+
+// IncomeBucket — оплаты за один интервал отчёта, Start — его начало в часовом
+// поясе фотографа.
+type IncomeBucket struct {
+	Start  time.Time `json:"start"`
+	Amount Money     `json:"amount"`
+	Count  int       `json:"count"`
+}
+
+// AgingReport — непогашенная задолженность на дату AsOf по давности начислений.
+// Оплаты гасят начисления клиента по порядку, начиная с самого старого.
+type AgingReport struct {
+	AsOf    string       `json:"as_of"`
+	Total   AgingTotal   `json:"total"`
+	Clients []AgingTotal `json:"clients"`
+}
+
+// AgingTotal — задолженность клиента (или итог по всем клиентам) по корзинам
+// давности в днях.
+type AgingTotal struct {
+	ClientID   ClientID `json:"client_id,omitempty"`
+	ClientName string   `json:"client_name,omitempty"`
+	Days0To30  Money    `json:"days_0_30"`
+	Days31To60 Money    `json:"days_31_60"`
+	Days61To90 Money    `json:"days_61_90"`
+	Over90     Money    `json:"days_over_90"`
+	Total      Money    `json:"total"`
+}
+
+// Statement — выписка по клиенту за период [From, To): входящий баланс, все
+// начисления и оплаты по порядку с балансом после каждой операции и исходящий баланс.
+type Statement struct {
+	ClientID   ClientID         `json:"client_id"`
+	ClientName string           `json:"client_name"`
+	From       *time.Time       `json:"from,omitempty"`
+	To         time.Time        `json:"to"`
+	Opening    Balance          `json:"opening"`
+	Entries    []StatementEntry `json:"entries"`
+	Charged    Money            `json:"charged"`
+	Paid       Money            `json:"paid"`
+	Closing    Balance          `json:"closing"`
+}
+
+// Источники операций в выписке: движение по начислениям или по оплатам.
+const (
+	StatementDebt    = "debt"
+	StatementPayment = "payment"
+)
+
+// StatementEntry — операция выписки. Kind — вид движения в своём источнике:
+// charge, adjustment или void у начислений, payment, refund или void у оплат;
+// ReversalOf у сторно, корректировок и возвратов указывает на исходную строку.
+// Amount начисления и оплаты положителен, у возврата и сторно оплаты
+// отрицателен, у сторно возврата положителен. Сторно и корректировки начисления
+// уменьшают или увеличивают долг.
+type StatementEntry struct {
+	Source      string    `json:"source"`
+	Kind        string    `json:"kind"`
+	ID          int64     `json:"id"`
+	ReversalOf  *int64    `json:"reversal_of,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	Description string    `json:"description,omitempty"`
+	Amount      Money     `json:"amount"`
+	Balance     Balance   `json:"balance"`
+}
+
+// Статусы счёта. Номер счёт получает при выставлении, тогда же на клиента
+// начисляются позиции, которые ещё не были начислены; аннулирование выставленного
+// счёта сторнирует эти начисления. Статус paid не задаётся вручную: выставленный
+// счёт оплачен, когда оплаты клиента покрывают все его начисления.
+const (
+	InvoiceDraft = "draft"
+	InvoiceSent  = "sent"
+	InvoicePaid  = "paid"
+	InvoiceVoid  = "void"
+)
+
+var invoiceTransitions = map[string][]string{
+	InvoiceDraft: {InvoiceSent, InvoiceVoid},
+	InvoiceSent:  {InvoiceVoid},
+}
+
+// CanTransitInvoice сообщает, можно ли перевести счёт из статуса from в to.
+func CanTransitInvoice(from, to string) bool {
+	for _, status := range invoiceTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+type Invoice struct {
+	ID             InvoiceID      `json:"id"`
+	PhotographerID PhotographerID `json:"photographer_id"`
+	ClientID       ClientID       `json:"client_id"`
+	ClientName     string         `json:"client_name"`
+	Number         string         `json:"number,omitempty"`
+	Status         string         `json:"status"`
+	IssueDate      *time.Time     `json:"issue_date"`
+	DueDate        *time.Time     `json:"due_date"`
+	Note           string         `json:"note"`
+	Items          []InvoiceItem  `json:"items,omitempty"`
+	Total          Money          `json:"total"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// InvoiceItem — позиция счёта. DebtID ссылается на начисление позиции: уже
+// проведённое до счёта или начисленное при его выставлении.
+type InvoiceItem struct {
+	DebtID      *DebtID `json:"debt_id,omitempty"`
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   Money   `json:"unit_price"`
+	Amount      Money   `json:"amount"`
+}
+
+// Статусы съёмки. При подтверждении на клиента начисляется цена съёмки, отмена
+// подтверждённой съёмки сторнирует начисление, изменение цены проводится корректировкой.
+const (
+	SessionTentative = "tentative"
+	SessionConfirmed = "confirmed"
+	SessionCompleted = "completed"
+	SessionCancelled = "cancelled"
+)
+
+var sessionTransitions = map[string][]string{
+	SessionTentative: {SessionConfirmed, SessionCancelled},
+	SessionConfirmed: {SessionCompleted, SessionCancelled},
+}
+
+// CanTransitSession сообщает, можно ли перевести съёмку из статуса from в to.
+func CanTransitSession(from, to string) bool {
+	for _, status := range sessionTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Session — съёмка фотографа у клиента в полуинтервале [StartsAt, EndsAt).
+type Session struct {
+	ID             SessionID      `json:"id"`
+	PhotographerID PhotographerID `json:"photographer_id"`
+	ClientID       ClientID       `json:"client_id"`
+	ClientName     string         `json:"client_name"`
+	StartsAt       time.Time      `json:"starts_at"`
+	EndsAt         time.Time      `json:"ends_at"`
+	Location       string         `json:"location"`
+	Type           string         `json:"type"`
+	Status         string         `json:"status"`
+	Price          Money          `json:"price"`
+	Note           string         `json:"note"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// CalendarFeed — лента съёмок фотографа для подписки из календаря.
+type CalendarFeed struct {
+	Photographer Photographer
+	Events       []CalendarEvent
+}
+
+// CalendarEvent — съёмка в ленте вместе с текущим балансом её клиента.
+type CalendarEvent struct {
+	Session
+	ClientBalance Balance
+}
+
+// Package — пакет услуг из прайс-листа фотографа. Архивный пакет остаётся у
+// начислений, которые по нему сделаны, но начислить его заново нельзя.
+type Package struct {
+	ID              PackageID      `json:"id"`
+	PhotographerID  PhotographerID `json:"photographer_id"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	DurationMinutes int            `json:"duration_minutes"`
+	Price           Money          `json:"price"`
+	Deliverables    []string       `json:"deliverables"`
+	Addons          []Addon        `json:"addons"`
+	ArchivedAt      *time.Time     `json:"archived_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// Addon — дополнительная опция пакета, например лишний час съёмки.
+type Addon struct {
+	ID    AddonID `json:"id"`
+	Name  string  `json:"name"`
+	Price Money   `json:"price"`
+}
+
+// PackageCharge — начисление клиенту по пакету: Quantity пакетов вместе с
+// выбранными опциями.
+type PackageCharge struct {
+	PhotographerID PhotographerID
+	ClientID       ClientID
+	PackageID      PackageID
+	Quantity       int
+	Addons         []AddonQuantity
+	Description    string
+	DueDate        *time.Time
+}
+
+type AddonQuantity struct {
+	ID       AddonID
+	Quantity int
+}
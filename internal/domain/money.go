@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultCurrency — валюта фотографа, если при регистрации она не указана.
+const DefaultCurrency = "RUB"
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Money — сумма в минимальных единицах валюты (копейках, центах) с кодом ISO 4217.
+// Складывать и вычитать можно только суммы в одной валюте.
+type Money struct {
+	Amount   int64  `json:"amount" example:"150050"`
+	Currency string `json:"currency" example:"RUB"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ValidCurrency проверяет, что код похож на код ISO 4217.
+func ValidCurrency(currency string) bool {
+	return currencyPattern.MatchString(currency)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// String форматирует сумму в основных единицах: "1500.50 RUB".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Decimal форматирует сумму в основных единицах без валюты: "1500.50".
+func (m Money) Decimal() string {
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+func (m Money) checkCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return NewError(ErrValidation, "currency mismatch: %s and %s", m.Currency, other.Currency)
+	}
+	return nil
+}
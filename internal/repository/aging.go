@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+// GetAging раскладывает непогашенные начисления по давности на момент asOf.
+// Оплаты клиента гасят его начисления по порядку: начисление погашено, если
+// сумма оплат покрывает его вместе со всеми более ранними. Давность считается
+// в днях по календарю часового пояса фотографа. Сторно и корректировки меняют
+// сумму исходного начисления, а не его давность. Первой строкой идёт общий итог.
+func (r *Repository) GetAging(ctx context.Context, photographerID domain.PhotographerID, asOf time.Time, includeDeleted bool) (domain.AgingTotal, []domain.AgingTotal, error) {
+	query := `
+		with settings as (
+			select time_zone as tz, currency,
+			       (($2::timestamptz - interval '1 microsecond') at time zone time_zone)::date as as_of_date
+			from photographers
+			where id = $1
+		), charges as (
+			select d.client_id, n.amount, d.occurred_at,
+			       sum(n.amount) over (partition by d.client_id order by d.occurred_at, d.id) as cumulative
+			from debts d
+			cross join lateral (
+				select d.amount + coalesce(sum(a.amount), 0) as amount
+				from debts a
+				where a.reversal_of = d.id and a.occurred_at < $2
+			) n
+			where d.photographer_id = $1 and d.reversal_of is null and d.occurred_at < $2
+		), paid as (
+			select client_id, sum(amount) as total
+			from payments
+			where photographer_id = $1 and occurred_at < $2
+			group by client_id
+		), aged as (
+			select ch.client_id,
+			       greatest(0, least(ch.amount, ch.cumulative - coalesce(p.total, 0))) as amount,
+			       s.as_of_date - (ch.occurred_at at time zone s.tz)::date as age
+			from charges ch
+			cross join settings s
+			left join paid p on p.client_id = ch.client_id
+		), totals as (
+			select grouping(c.id) = 1 as is_total, c.id, c.name,
+			       coalesce(sum(a.amount) filter (where a.age <= 30), 0) as days_0_30,
+			       coalesce(sum(a.amount) filter (where a.age between 31 and 60), 0) as days_31_60,
+			       coalesce(sum(a.amount) filter (where a.age between 61 and 90), 0) as days_61_90,
+			       coalesce(sum(a.amount) filter (where a.age > 90), 0) as days_over_90,
+			       coalesce(sum(a.amount), 0) as total
+			from aged a
+			join clients c on c.id = a.client_id
+			where a.amount > 0 and ($3::boolean or c.deleted_at is null)
+			group by grouping sets ((c.id, c.name), ())
+		)
+		select coalesce(t.id, 0), coalesce(t.name, ''),
+		       t.days_0_30, t.days_31_60, t.days_61_90, t.days_over_90, t.total, s.currency
+		from totals t
+		cross join settings s
+		order by t.is_total desc, t.total desc, t.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, photographerID, asOf, includeDeleted)
+	if err != nil {
+		return domain.AgingTotal{}, nil, fmt.Errorf("failed to get aging: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []domain.AgingTotal
+	for rows.Next() {
+		var (
+			total    domain.AgingTotal
+			currency string
+		)
+		if err = rows.Scan(&total.ClientID, &total.ClientName, &total.Days0To30.Amount, &total.Days31To60.Amount,
+			&total.Days61To90.Amount, &total.Over90.Amount, &total.Total.Amount, &currency); err != nil {
+			return domain.AgingTotal{}, nil, fmt.Errorf("failed to scan aging: %w", err)
+		}
+		total.Days0To30.Currency = currency
+		total.Days31To60.Currency = currency
+		total.Days61To90.Currency = currency
+		total.Over90.Currency = currency
+		total.Total.Currency = currency
+		totals = append(totals, total)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.AgingTotal{}, nil, fmt.Errorf("failed to scan aging: %w", err)
+	}
+
+	if len(totals) == 0 {
+		return domain.AgingTotal{}, nil, domain.NewError(domain.ErrNotFound, "photographer %d not found", photographerID)
+	}
+
+	return totals[0], totals[1:], nil
+}
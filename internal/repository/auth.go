@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+// GetPhotographerCredentials возвращает ID и хеш пароля фотографа по логину.
+// Деактивированный фотограф не найдётся: войти заново он не может.
+func (r *Repository) GetPhotographerCredentials(ctx context.Context, login string) (domain.PhotographerID, string, error) {
+	query := `
+		select id, password_hash
+		from photographers
+		where lower(login) = lower($1) and password_hash is not null and deactivated_at is null
+	`
+
+	var (
+		id           domain.PhotographerID
+		passwordHash string
+	)
+	err := r.db.QueryRowContext(ctx, query, login).Scan(&id, &passwordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", domain.NewError(domain.ErrNotFound, "photographer with login %q not found", login)
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get photographer credentials: %w", err)
+	}
+
+	return id, passwordHash, nil
+}
+
+func (r *Repository) CreateAuthToken(ctx context.Context, tokenHash string, photographerID domain.PhotographerID, expiresAt time.Time) error {
+	query := `
+		insert into auth_tokens (token_hash, photographer_id, expires_at)
+		values ($1, $2, $3)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, tokenHash, photographerID, expiresAt); err != nil {
+		return fmt.Errorf("failed to create auth token: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAuthToken(ctx context.Context, tokenHash string) (domain.AuthToken, error) {
+	query := `
+		select t.photographer_id, t.expires_at, p.deactivated_at is not null
+		from auth_tokens t
+		join photographers p on p.id = t.photographer_id
+		where t.token_hash = $1
+	`
+
+	var token domain.AuthToken
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&token.PhotographerID, &token.ExpiresAt, &token.Deactivated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.AuthToken{}, domain.NewError(domain.ErrUnauthorized, "invalid token")
+	}
+	if err != nil {
+		return domain.AuthToken{}, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *Repository) DeleteAuthToken(ctx context.Context, tokenHash string) error {
+	query := `delete from auth_tokens where token_hash = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, tokenHash); err != nil {
+		return fmt.Errorf("failed to delete auth token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteExpiredAuthTokens(ctx context.Context) (int64, error) {
+	query := `delete from auth_tokens where expires_at < now()`
+
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired auth tokens: %w", err)
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+// SetCalendarToken сохраняет хэш токена ленты календаря фотографа, пустой хэш
+// отключает ленту. Прежний токен перестаёт действовать сразу.
+func (r *Repository) SetCalendarToken(ctx context.Context, photographerID domain.PhotographerID, tokenHash string) error {
+	query := `update photographers set calendar_token_hash = nullif($2, '') where id = $1`
+
+	res, err := r.db.ExecContext(ctx, query, photographerID, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to set calendar token: %w", translateError(err))
+	}
+
+	return checkAffected(res, "photographer %d not found", photographerID)
+}
+
+func (r *Repository) GetCalendarPhotographer(ctx context.Context, tokenHash string) (domain.PhotographerID, error) {
+	query := `select id from photographers where calendar_token_hash = $1`
+
+	var id domain.PhotographerID
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.NewError(domain.ErrNotFound, "calendar not found")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get calendar photographer: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetCalendarEvents возвращает съёмки фотографа, которые заканчиваются после
+// since, вместе с балансом клиента. Съёмки удалённых клиентов в ленту не идут.
+func (r *Repository) GetCalendarEvents(ctx context.Context, photographerID domain.PhotographerID, since time.Time) ([]domain.CalendarEvent, error) {
+	query := fmt.Sprintf(`
+		select %s,
+		       coalesce((select sum(amount) from debts where client_id = c.id), 0) -
+		       coalesce((select sum(amount) from payments where client_id = c.id), 0)
+		from sessions s
+		join clients c on c.id = s.client_id
+		where s.photographer_id = $1 and s.ends_at > $2 and c.deleted_at is null
+		order by s.starts_at, s.id
+	`, sessionColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, photographerID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []domain.CalendarEvent
+	for rows.Next() {
+		var (
+			event domain.CalendarEvent
+			net   int64
+		)
+		if err = rows.Scan(append(scanSession(&event.Session), &net)...); err != nil {
+			return nil, fmt.Errorf("failed to scan calendar event: %w", err)
+		}
+		event.ClientBalance = domain.NewBalance(domain.NewMoney(net, event.Price.Currency))
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get calendar events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (r *Repository) CreateClientField(ctx context.Context, field domain.ClientField) (domain.ClientFieldID, error) {
+	query := `
+		insert into client_fields (photographer_id, key, label, type, options)
+		values ($1, $2, $3, $4, $5)
+		returning id
+	`
+
+	var id domain.ClientFieldID
+	err := r.db.QueryRowContext(ctx, query, field.PhotographerID, field.Key, field.Label, field.Type,
+		pq.Array(field.Options)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create client field: %w", translateError(err))
+	}
+
+	return id, nil
+}
+
+// UpdateClientField меняет подпись и варианты поля. Ключ и тип не меняются:
+// по ним хранятся и проверяются значения у клиентов.
+func (r *Repository) UpdateClientField(ctx context.Context, field domain.ClientField) error {
+	query := `update client_fields set label = $2, options = $3 where id = $1`
+
+	res, err := r.db.ExecContext(ctx, query, field.ID, field.Label, pq.Array(field.Options))
+	if err != nil {
+		return fmt.Errorf("failed to update client field: %w", translateError(err))
+	}
+
+	return checkAffected(res, "client field %d not found", field.ID)
+}
+
+// DeleteClientField удаляет поле вместе с его значениями у всех клиентов фотографа.
+func (r *Repository) DeleteClientField(ctx context.Context, id domain.ClientFieldID) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		var (
+			photographerID domain.PhotographerID
+			key            string
+		)
+		err := tx.QueryRowContext(ctx, `delete from client_fields where id = $1 returning photographer_id, key`,
+			id).Scan(&photographerID, &key)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewError(domain.ErrNotFound, "client field %d not found", id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete client field: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			update clients set custom_fields = custom_fields - $2
+			where photographer_id = $1 and custom_fields ? $2
+		`, photographerID, key)
+		if err != nil {
+			return fmt.Errorf("failed to delete client field values: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) GetClientField(ctx context.Context, id domain.ClientFieldID) (domain.ClientField, error) {
+	query := `
+		select id, photographer_id, key, label, type, options, created_at
+		from client_fields
+		where id = $1
+	`
+
+	var field domain.ClientField
+	err := r.db.QueryRowContext(ctx, query, id).Scan(scanClientField(&field)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ClientField{}, domain.NewError(domain.ErrNotFound, "client field %d not found", id)
+	}
+	if err != nil {
+		return domain.ClientField{}, fmt.Errorf("failed to get client field: %w", err)
+	}
+
+	return field, nil
+}
+
+// GetClientFields возвращает поля клиентов фотографа в порядке их создания.
+func (r *Repository) GetClientFields(ctx context.Context, photographerID domain.PhotographerID) ([]domain.ClientField, error) {
+	query := `
+		select id, photographer_id, key, label, type, options, created_at
+		from client_fields
+		where photographer_id = $1
+		order by id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, photographerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client fields: %w", err)
+	}
+	defer rows.Close()
+
+	var fields []domain.ClientField
+	for rows.Next() {
+		var field domain.ClientField
+		if err = rows.Scan(scanClientField(&field)...); err != nil {
+			return nil, fmt.Errorf("failed to scan client field: %w", err)
+		}
+		fields = append(fields, field)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan client field: %w", err)
+	}
+
+	return fields, nil
+}
+
+func scanClientField(f *domain.ClientField) []any {
+	return []any{&f.ID, &f.PhotographerID, &f.Key, &f.Label, &f.Type, pq.Array(&f.Options), &f.CreatedAt}
+}
+
+func scanClient(c *domain.Client) []any {
+	return []any{&c.ID, &c.PhotographerID, &c.Name, jsonColumn{&c.Contacts}, &c.Birthday, &c.Source, &c.Notes,
+		jsonColumn{&c.CustomFields}, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt}
+}
+
+// clientJSON готовит контакты и свои поля клиента к записи в колонки JSONB.
+func clientJSON(client domain.Client) (contacts, customFields []byte, err error) {
+	if client.Contacts == nil {
+		client.Contacts = []domain.Contact{}
+	}
+	if client.CustomFields == nil {
+		client.CustomFields = map[string]any{}
+	}
+
+	if contacts, err = json.Marshal(client.Contacts); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode client contacts: %w", err)
+	}
+	if customFields, err = json.Marshal(client.CustomFields); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode client custom fields: %w", err)
+	}
+
+	return contacts, customFields, nil
+}
+
+// jsonColumn читает колонку JSONB в значение, на которое указывает dest.
+type jsonColumn struct {
+	dest any
+}
+
+func (c jsonColumn) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("unsupported json column type %T", src)
+	}
+
+	return json.Unmarshal(data, c.dest)
+}
+
+// dateValue передаёт дату без времени строкой, чтобы база не сдвинула её при
+// переводе метки времени в дату.
+func dateValue(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.DateOnly)
+}
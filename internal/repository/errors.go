@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// constraintMessages описывает нарушения ограничений понятным клиенту текстом.
+var constraintMessages = map[string]string{
+	"fk_photographer_id": "photographer not found",
+	"fk_client_id":       "client not found",
+
+	"unique_photographer_login": "login is already taken",
+	"exclude_session_overlap":   "session overlaps another session of the photographer",
+	"unique_package_name":       "package with this name already exists",
+	"unique_client_field_key":   "client field with this key already exists",
+}
+
+// translateError переводит ошибки драйвера в доменные, не раскрывая деталей SQL.
+// Неизвестные ошибки возвращаются как есть.
+func translateError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewError(domain.ErrNotFound, "not found")
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	message, ok := constraintMessages[pqErr.Constraint]
+
+	switch pqErr.Code {
+	case "23503": // foreign_key_violation
+		if !ok {
+			message = "referenced entity not found"
+		}
+		return domain.NewError(domain.ErrNotFound, message)
+	case "23505": // unique_violation
+		if !ok {
+			message = "entity already exists"
+		}
+		return domain.NewError(domain.ErrConflict, message)
+	case "23P01": // exclusion_violation
+		if !ok {
+			message = "entity conflicts with an existing one"
+		}
+		return domain.NewError(domain.ErrConflict, message)
+	case "23502", "23514", "22P02", "22003": // not_null, check, invalid_text_representation, numeric_value_out_of_range
+		if !ok {
+			message = "invalid value"
+		}
+		return domain.NewError(domain.ErrValidation, message)
+	}
+
+	return err
+}
+
+// checkAffected возвращает ErrNotFound, если запрос не затронул ни одной строки.
+func checkAffected(res sql.Result, format string, args ...any) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.NewError(domain.ErrNotFound, format, args...)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"photographer/internal/domain"
+)
+
+// ReserveIdempotencyKey занимает ключ за запросом. Если ключ уже занят и не истёк,
+// возвращается сохранённая запись и false.
+func (r *Repository) ReserveIdempotencyKey(ctx context.Context, record domain.IdempotencyRecord, ttl time.Duration) (domain.IdempotencyRecord, bool, error) {
+	var (
+		stored  domain.IdempotencyRecord
+		created bool
+	)
+
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			delete from idempotency_keys
+			where key = $1 and scope = $2 and created_at < now() - make_interval(secs => $3)
+		`, record.Key, record.Scope, ttl.Seconds())
+		if err != nil {
+			return fmt.Errorf("failed to delete expired idempotency key: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx, `
+			insert into idempotency_keys (key, scope, request_hash)
+			values ($1, $2, $3)
+			on conflict (key, scope) do nothing
+		`, record.Key, record.Scope, record.RequestHash)
+		if err != nil {
+			return fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+
+		if affected > 0 {
+			stored, created = record, true
+			return nil
+		}
+
+		var (
+			statusCode  sql.NullInt64
+			contentType sql.NullString
+		)
+		err = tx.QueryRowContext(ctx, `
+			select request_hash, status_code, content_type, body, created_at
+			from idempotency_keys
+			where key = $1 and scope = $2
+		`, record.Key, record.Scope).Scan(&stored.RequestHash, &statusCode, &contentType, &stored.Body, &stored.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+
+		stored.Key, stored.Scope = record.Key, record.Scope
+		stored.StatusCode = int(statusCode.Int64)
+		stored.ContentType = contentType.String
+		created = false
+
+		return nil
+	})
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	return stored, created, nil
+}
+
+func (r *Repository) SaveIdempotencyResult(ctx context.Context, record domain.IdempotencyRecord) error {
+	query := `
+		update idempotency_keys
+		set status_code = $3, content_type = $4, body = $5
+		where key = $1 and scope = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		record.Key, record.Scope, record.StatusCode, record.ContentType, record.Body)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency result: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, key, scope string) error {
+	query := `delete from idempotency_keys where key = $1 and scope = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, key, scope); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteExpiredIdempotencyKeys(ctx context.Context, ttl time.Duration) (int64, error) {
+	query := `delete from idempotency_keys where created_at < now() - make_interval(secs => $1)`
+
+	res, err := r.db.ExecContext(ctx, query, ttl.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return res.RowsAffected()
+}
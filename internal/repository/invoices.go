@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+func (r *Repository) CreateInvoice(ctx context.Context, invoice domain.Invoice) (domain.InvoiceID, error) {
+	query := `
+		insert into invoices (photographer_id, client_id, due_date, note, currency)
+		values ($1, $2, $3, $4, $5)
+		returning id
+	`
+
+	var id domain.InvoiceID
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, invoice.PhotographerID, invoice.ClientID,
+			invoice.DueDate, invoice.Note, invoice.Total.Currency).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to create invoice: %w", translateError(err))
+		}
+
+		return insertInvoiceItems(ctx, tx, id, invoice.Items)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// UpdateInvoice заменяет срок оплаты, примечание и позиции черновика счёта.
+func (r *Repository) UpdateInvoice(ctx context.Context, invoice domain.Invoice) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		locked, err := lockInvoice(ctx, tx, invoice.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != domain.InvoiceDraft {
+			return domain.NewError(domain.ErrConflict, "invoice %d is %s, only drafts can be edited", invoice.ID, locked.Status)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			update invoices set due_date = $2, note = $3, currency = $4, updated_at = now()
+			where id = $1
+		`, invoice.ID, invoice.DueDate, invoice.Note, invoice.Total.Currency)
+		if err != nil {
+			return fmt.Errorf("failed to update invoice: %w", translateError(err))
+		}
+
+		if _, err = tx.ExecContext(ctx, `delete from invoice_items where invoice_id = $1`, invoice.ID); err != nil {
+			return fmt.Errorf("failed to delete invoice items: %w", err)
+		}
+
+		return insertInvoiceItems(ctx, tx, invoice.ID, invoice.Items)
+	})
+}
+
+// debtNet добавляет к начислению d его сумму n.amount вместе со сторно и корректировками.
+const debtNet = `
+	cross join lateral (
+		select d.amount + coalesce(sum(a.amount), 0) as amount from debts a where a.reversal_of = d.id
+	) n
+`
+
+// invoiceStatus — статус счёта. Выставленный счёт оплачен, когда оплаты клиента
+// покрывают каждое его начисление вместе со всеми более ранними начислениями
+// клиента, так же как в отчёте о давности задолженности.
+var invoiceStatus = fmt.Sprintf(`
+	case when i.status = 'sent' and not exists (
+		select 1
+		from (
+			select d.id, sum(n.amount) over (order by d.occurred_at, d.id) as cumulative
+			from debts d
+			%s
+			where d.client_id = i.client_id and d.reversal_of is null
+		) ch
+		where ch.cumulative > (select coalesce(sum(p.amount), 0) from payments p where p.client_id = i.client_id)
+		  and ch.id in (
+			select debt_id from invoice_items where invoice_id = i.id
+			union
+			select id from debts where invoice_id = i.id and reversal_of is null
+		  )
+	) then 'paid' else i.status end
+`, debtNet)
+
+var invoiceColumns = fmt.Sprintf(`
+	i.id, i.photographer_id, i.client_id, c.name as client_name, coalesce(i.number, '') as number, %s as status,
+	i.issue_date, i.due_date, i.note, i.currency, i.created_at, i.updated_at,
+	(select coalesce(sum(quantity * unit_price), 0) from invoice_items where invoice_id = i.id) as total
+`, invoiceStatus)
+
+func (r *Repository) GetInvoice(ctx context.Context, id domain.InvoiceID) (domain.Invoice, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from invoices i
+		join clients c on c.id = i.client_id
+		where i.id = $1
+	`, invoiceColumns)
+
+	var invoice domain.Invoice
+	err := r.db.QueryRowContext(ctx, query, id).Scan(scanInvoice(&invoice)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Invoice{}, domain.NewError(domain.ErrNotFound, "invoice %d not found", id)
+	}
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		select debt_id, description, quantity, unit_price, quantity * unit_price
+		from invoice_items
+		where invoice_id = $1
+		order by position
+	`, id)
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to get invoice items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := domain.InvoiceItem{
+			UnitPrice: domain.NewMoney(0, invoice.Total.Currency),
+			Amount:    domain.NewMoney(0, invoice.Total.Currency),
+		}
+		if err = rows.Scan(&item.DebtID, &item.Description, &item.Quantity, &item.UnitPrice.Amount, &item.Amount.Amount); err != nil {
+			return domain.Invoice{}, fmt.Errorf("failed to scan invoice item: %w", err)
+		}
+		invoice.Items = append(invoice.Items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to scan invoice item: %w", err)
+	}
+
+	return invoice, nil
+}
+
+var invoicesList = listSpec{
+	sorts: map[string]sortColumn{
+		"amount": {column: "total", cast: "numeric"},
+		"date":   {column: "created_at", cast: "timestamptz"},
+	},
+	defaultSort:   "-date",
+	dateColumn:    "created_at",
+	amountColumn:  "total",
+	clientColumn:  "client_id",
+	deletedColumn: "deleted_at",
+}
+
+// GetInvoices возвращает счета фотографа без позиций. Пустой status означает
+// счета в любом статусе, paid — выставленные счета, покрытые оплатами.
+func (r *Repository) GetInvoices(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Invoice], error) {
+	base := fmt.Sprintf(`
+		select *
+		from (
+			select %s, c.deleted_at
+			from invoices i
+			join clients c on c.id = i.client_id
+			where i.photographer_id = $1
+		) i
+		where $2 = '' or i.status = $2
+	`, invoiceColumns)
+
+	query, args, err := invoicesList.build(base,
+		"t.id, t.photographer_id, t.client_id, t.client_name, t.number, t.status, t.issue_date, t.due_date, "+
+			"t.note, t.currency, t.created_at, t.updated_at, t.total",
+		[]any{photographerID, status}, &params)
+	if err != nil {
+		return domain.Page[domain.Invoice]{}, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.Page[domain.Invoice]{}, fmt.Errorf("failed to get invoices: %w", err)
+	}
+
+	page, err := scanPage(rows, params, scanInvoice, func(i domain.Invoice) int64 { return int64(i.ID) })
+	if err != nil {
+		return domain.Page[domain.Invoice]{}, fmt.Errorf("failed to scan invoice: %w", err)
+	}
+
+	return page, nil
+}
+
+// IssueInvoice выставляет черновик счёта: присваивает ему следующий номер
+// фотографа за год выставления и начисляет клиенту позиции, которые не ссылаются
+// на уже проведённые начисления. Позиция с начислением, изменённым после
+// составления черновика, не выставляется: черновик нужно обновить.
+func (r *Repository) IssueInvoice(ctx context.Context, id domain.InvoiceID, issueDate time.Time) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		invoice, err := lockInvoice(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !domain.CanTransitInvoice(invoice.Status, domain.InvoiceSent) {
+			return domain.NewError(domain.ErrConflict, "invoice %d is %s and cannot be issued", id, invoice.Status)
+		}
+
+		if err = lockActiveClient(ctx, tx, invoice.ClientID); err != nil {
+			return err
+		}
+
+		var seq int
+		err = tx.QueryRowContext(ctx, `
+			insert into invoice_counters (photographer_id, year, last_number)
+			values ($1, $2, 1)
+			on conflict (photographer_id, year) do update set last_number = invoice_counters.last_number + 1
+			returning last_number
+		`, invoice.PhotographerID, issueDate.Year()).Scan(&seq)
+		if err != nil {
+			return fmt.Errorf("failed to take invoice number: %w", err)
+		}
+		number := fmt.Sprintf("%d-%04d", issueDate.Year(), seq)
+
+		_, err = tx.ExecContext(ctx, `
+			update invoices set number = $2, status = $3, issue_date = $4, updated_at = now()
+			where id = $1
+		`, id, number, domain.InvoiceSent, issueDate)
+		if err != nil {
+			return fmt.Errorf("failed to issue invoice: %w", translateError(err))
+		}
+
+		items, err := invoiceItemDebts(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if item.debtID != nil {
+				if item.net != item.amount {
+					return domain.NewError(domain.ErrConflict,
+						"debt %d was changed after the invoice %d was drafted, update the invoice", *item.debtID, id)
+				}
+				continue
+			}
+
+			var debtID domain.DebtID
+			err = tx.QueryRowContext(ctx, `
+				insert into debts (photographer_id, client_id, amount, currency, description, due_date, invoice_id)
+				values ($1, $2, $3, $4, $5, $6, $7)
+				returning id
+			`, invoice.PhotographerID, invoice.ClientID, item.amount, invoice.Total.Currency,
+				fmt.Sprintf("Счёт № %s: %s", number, item.description), invoice.DueDate, id).Scan(&debtID)
+			if err != nil {
+				return fmt.Errorf("failed to add invoice debt: %w", translateError(err))
+			}
+
+			if _, err = tx.ExecContext(ctx, `update invoice_items set debt_id = $2 where id = $1`, item.id, debtID); err != nil {
+				return fmt.Errorf("failed to link invoice item debt: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// invoiceItemDebt — позиция счёта с суммой и остатком её начисления.
+type invoiceItemDebt struct {
+	id          int64
+	debtID      *domain.DebtID
+	description string
+	amount      int64
+	net         int64
+}
+
+// invoiceItemDebts возвращает позиции счёта по порядку. У позиции, ссылающейся на
+// начисление, net — сумма начисления вместе со сторно и корректировками.
+func invoiceItemDebts(ctx context.Context, tx *sql.Tx, id domain.InvoiceID) ([]invoiceItemDebt, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		select ii.id, ii.debt_id, ii.description, ii.quantity * ii.unit_price, coalesce(net.amount, 0)
+		from invoice_items ii
+		left join (
+			select d.id, n.amount
+			from debts d
+			%s
+		) net on net.id = ii.debt_id
+		where ii.invoice_id = $1
+		order by ii.position
+	`, debtNet), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []invoiceItemDebt
+	for rows.Next() {
+		var item invoiceItemDebt
+		if err = rows.Scan(&item.id, &item.debtID, &item.description, &item.amount, &item.net); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan invoice item: %w", err)
+	}
+
+	return items, nil
+}
+
+// SetInvoiceStatus аннулирует счёт. Начисления, проведённые при выставлении
+// счёта, сторнируются, номер остаётся за счётом. Начисления, проведённые до счёта
+// и выставленные в нём, остаются на клиенте. Оплаченный счёт аннулировать нельзя.
+func (r *Repository) SetInvoiceStatus(ctx context.Context, id domain.InvoiceID, status string) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		invoice, err := lockInvoice(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !domain.CanTransitInvoice(invoice.Status, status) {
+			return domain.NewError(domain.ErrConflict, "invoice %d is %s and cannot become %s", id, invoice.Status, status)
+		}
+
+		if status == domain.InvoiceVoid && invoice.Status == domain.InvoiceSent {
+			if err = lockClient(ctx, tx, invoice.ClientID); err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+				insert into debts (photographer_id, client_id, kind, amount, currency, description, reversal_of, invoice_id)
+				select d.photographer_id, d.client_id, $2, -n.amount, d.currency, 'Сторно: ' || d.description, d.id, d.invoice_id
+				from debts d
+				%s
+				where d.invoice_id = $1 and d.reversal_of is null and n.amount <> 0
+			`, debtNet), id, domain.DebtKindVoid)
+			if err != nil {
+				return fmt.Errorf("failed to void invoice debts: %w", translateError(err))
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `update invoices set status = $2, updated_at = now() where id = $1`, id, status)
+		if err != nil {
+			return fmt.Errorf("failed to set invoice status: %w", translateError(err))
+		}
+
+		return nil
+	})
+}
+
+// lockInvoice блокирует счёт до конца транзакции и возвращает его без позиций.
+func lockInvoice(ctx context.Context, tx *sql.Tx, id domain.InvoiceID) (domain.Invoice, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from invoices i
+		join clients c on c.id = i.client_id
+		where i.id = $1
+		for update of i
+	`, invoiceColumns)
+
+	var invoice domain.Invoice
+	err := tx.QueryRowContext(ctx, query, id).Scan(scanInvoice(&invoice)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Invoice{}, domain.NewError(domain.ErrNotFound, "invoice %d not found", id)
+	}
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to lock invoice %d: %w", id, err)
+	}
+
+	return invoice, nil
+}
+
+// insertInvoiceItems добавляет позиции счёта. Начисление можно выставить только
+// в одном не аннулированном счёте.
+func insertInvoiceItems(ctx context.Context, tx *sql.Tx, id domain.InvoiceID, items []domain.InvoiceItem) error {
+	query := `
+		insert into invoice_items (invoice_id, position, debt_id, description, quantity, unit_price)
+		values ($1, $2, $3, $4, $5, $6)
+	`
+
+	for i, item := range items {
+		if item.DebtID != nil {
+			var other domain.InvoiceID
+			err := tx.QueryRowContext(ctx, `
+				select i.id
+				from invoices i
+				where i.id <> $2 and i.status <> 'void'
+				  and (exists (select 1 from invoice_items where invoice_id = i.id and debt_id = $1)
+				       or exists (select 1 from debts where id = $1 and invoice_id = i.id))
+				limit 1
+			`, *item.DebtID, id).Scan(&other)
+			if err == nil {
+				return domain.NewError(domain.ErrConflict, "debt %d is already on invoice %d", *item.DebtID, other)
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("failed to check invoice item debt: %w", err)
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, query, id, i, item.DebtID, item.Description, item.Quantity, item.UnitPrice.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to add invoice item: %w", translateError(err))
+		}
+	}
+
+	return nil
+}
+
+func scanInvoice(i *domain.Invoice) []any {
+	return []any{&i.ID, &i.PhotographerID, &i.ClientID, &i.ClientName, &i.Number, &i.Status,
+		&i.IssueDate, &i.DueDate, &i.Note, &i.Total.Currency, &i.CreatedAt, &i.UpdatedAt, &i.Total.Amount}
+}
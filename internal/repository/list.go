@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"photographer/internal/domain"
+	"sort"
+	"strings"
+)
+
+// sortColumn — колонка подзапроса, по которой сортирует ключ, и тип для
+// сравнения со значением из курсора.
+type sortColumn struct {
+	column string
+	cast   string
+}
+
+// listSpec описывает, какие сортировки и фильтры поддерживает список. Базовый
+// запрос оборачивается в подзапрос t, пустые колонки означают, что фильтр не
+// поддерживается.
+type listSpec struct {
+	sorts       map[string]sortColumn
+	defaultSort string
+
+	dateColumn    string
+	amountColumn  string
+	clientColumn  string
+	deletedColumn string
+}
+
+// build дополняет базовый запрос фильтрами, условием keyset-пагинации по курсору
+// и сортировкой. Последней колонкой выборки идёт значение ключа сортировки в
+// виде текста, его забирает scanPage для следующего курсора.
+func (s listSpec) build(base, columns string, args []any, params *domain.ListParams) (string, []any, error) {
+	if params.Sort == "" {
+		params.Sort = s.defaultSort
+	}
+
+	key := params.SortKey()
+	sortCol, ok := s.sorts[key]
+	if !ok {
+		keys := make([]string, 0, len(s.sorts))
+		for k := range s.sorts {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var v domain.ValidationError
+		v.Add("sort", "must be one of %s, optionally prefixed with '-'", strings.Join(keys, ", "))
+		return "", nil, v.Err()
+	}
+
+	where, args := s.filters(args, *params)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	direction, op := "asc", ">"
+	if params.Descending() {
+		direction, op = "desc", "<"
+	}
+
+	if params.Cursor != nil {
+		if params.Cursor.Sort != params.Sort {
+			var v domain.ValidationError
+			v.Add("cursor", "was issued for sort '%s'", params.Cursor.Sort)
+			return "", nil, v.Err()
+		}
+		where = append(where, fmt.Sprintf("(t.%s, t.id) %s (%s::%s, %s)",
+			sortCol.column, op, arg(params.Cursor.Value), sortCol.cast, arg(params.Cursor.ID)))
+	}
+
+	query := fmt.Sprintf("select %s, t.%s::text\nfrom (%s) t", columns, sortCol.column, base)
+	if len(where) > 0 {
+		query += "\nwhere " + strings.Join(where, " and ")
+	}
+	query += fmt.Sprintf("\norder by t.%s %s, t.id %s\nlimit %s",
+		sortCol.column, direction, direction, arg(params.Limit+1))
+
+	return query, args, nil
+}
+
+// filters возвращает условия фильтрации по подзапросу t без учёта курсора.
+func (s listSpec) filters(args []any, params domain.ListParams) ([]string, []any) {
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var where []string
+	if s.dateColumn != "" && params.From != nil {
+		where = append(where, fmt.Sprintf("t.%s >= %s", s.dateColumn, arg(*params.From)))
+	}
+	if s.dateColumn != "" && params.To != nil {
+		where = append(where, fmt.Sprintf("t.%s < %s", s.dateColumn, arg(*params.To)))
+	}
+	if s.clientColumn != "" && params.ClientID != 0 {
+		where = append(where, fmt.Sprintf("t.%s = %s", s.clientColumn, arg(params.ClientID)))
+	}
+	if s.amountColumn != "" && params.MinAmount != 0 {
+		where = append(where, fmt.Sprintf("t.%s >= %s", s.amountColumn, arg(params.MinAmount)))
+	}
+	if s.deletedColumn != "" && !params.IncludeDeleted {
+		where = append(where, fmt.Sprintf("t.%s is null", s.deletedColumn))
+	}
+
+	return where, args
+}
+
+// scanPage читает на одну запись больше лимита: если она есть, по последней
+// записи страницы строится курсор следующей.
+func scanPage[T any](rows *sql.Rows, params domain.ListParams, scan func(*T) []any, id func(T) int64) (domain.Page[T], error) {
+	defer rows.Close()
+
+	var (
+		page   domain.Page[T]
+		values []string
+	)
+	for rows.Next() {
+		var (
+			item  T
+			value sql.NullString
+		)
+		if err := rows.Scan(append(scan(&item), &value)...); err != nil {
+			return domain.Page[T]{}, err
+		}
+		page.Items = append(page.Items, item)
+		values = append(values, value.String)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Page[T]{}, err
+	}
+
+	if len(page.Items) > params.Limit {
+		page.Items = page.Items[:params.Limit]
+		last := page.Items[params.Limit-1]
+		page.NextCursor = domain.Cursor{
+			Sort:  params.Sort,
+			Value: values[params.Limit-1],
+			ID:    id(last),
+		}.Encode()
+	}
+
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"photographer/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// GetDuplicateCandidates возвращает пары неудалённых клиентов фотографа с общим
+// телефоном, общим email или похожими именами. Телефоны сравниваются по
+// последним десяти цифрам, чтобы +7 и 8 в начале номера не мешали.
+func (r *Repository) GetDuplicateCandidates(ctx context.Context, photographerID domain.PhotographerID,
+	minNameScore float64, limit int) ([]domain.DuplicateCandidate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		with active as (
+			select id, name, contacts
+			from clients
+			where photographer_id = $1 and deleted_at is null
+		),
+		contact_keys as (
+			select a.id,
+			       case when e->>'type' = 'email' then 'email' else 'phone' end as kind,
+			       case when e->>'type' = 'email' then lower(trim(e->>'value'))
+			            else right(regexp_replace(e->>'value', '\D', '', 'g'), 10) end as value
+			from active a, jsonb_array_elements(a.contacts) e
+			where e->>'type' in ('phone', 'whatsapp', 'email')
+		),
+		pairs as (
+			select x.id as first_id, y.id as second_id, x.kind as reason, 1::real as score
+			from contact_keys x
+			join contact_keys y on y.kind = x.kind and y.value = x.value and y.id > x.id
+			where x.value <> '' and (x.kind = 'email' or length(x.value) >= 7)
+			union
+			select x.id, y.id, 'name', similarity(lower(x.name), lower(y.name))
+			from active x
+			join active y on y.id > x.id
+			where similarity(lower(x.name), lower(y.name)) >= $2
+		),
+		grouped as (
+			select first_id, second_id, array_agg(distinct reason order by reason) as reasons, max(score) as score
+			from pairs
+			group by first_id, second_id
+		)
+		select a.id, a.photographer_id, a.name, a.contacts, a.birthday, a.source, a.notes, a.custom_fields,
+		       a.created_at, a.updated_at, a.deleted_at,
+		       coalesce((select sum(amount) from debts where client_id = a.id), 0)
+		           - coalesce((select sum(amount) from payments where client_id = a.id), 0), ph.currency,
+		       b.id, b.photographer_id, b.name, b.contacts, b.birthday, b.source, b.notes, b.custom_fields,
+		       b.created_at, b.updated_at, b.deleted_at,
+		       coalesce((select sum(amount) from debts where client_id = b.id), 0)
+		           - coalesce((select sum(amount) from payments where client_id = b.id), 0), ph.currency,
+		       g.reasons, g.score
+		from grouped g
+		join clients a on a.id = g.first_id
+		join clients b on b.id = g.second_id
+		join photographers ph on ph.id = a.photographer_id
+		order by g.score desc, g.first_id, g.second_id
+		limit $3
+	`, photographerID, minNameScore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get duplicate clients: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []domain.DuplicateCandidate
+	for rows.Next() {
+		var (
+			candidate domain.DuplicateCandidate
+			a, b      = &candidate.Client, &candidate.Duplicate
+		)
+		dest := append(scanClient(a), &a.Balance.Debt.Amount, &a.Balance.Debt.Currency)
+		dest = append(dest, scanClient(b)...)
+		dest = append(dest, &b.Balance.Debt.Amount, &b.Balance.Debt.Currency, pq.Array(&candidate.Reasons),
+			&candidate.Score)
+		if err = rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate clients: %w", err)
+		}
+		a.Balance = domain.NewBalance(a.Balance.Debt)
+		b.Balance = domain.NewBalance(b.Balance.Debt)
+		candidates = append(candidates, candidate)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan duplicate clients: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// MergeClients переносит к target начисления, оплаты, съёмки и счета клиента
+// source, записывает в target объединённую карточку, удаляет source и
+// сохраняет объединение в истории. Карточки собраны по данным, прочитанным до
+// транзакции, поэтому если кто-то успел изменить одного из клиентов, объединение
+// отменяется с конфликтом.
+func (r *Repository) MergeClients(ctx context.Context, target, source domain.Client) (domain.ClientMerge, domain.Balance, error) {
+	var (
+		merge   domain.ClientMerge
+		balance domain.Balance
+	)
+
+	contacts, customFields, err := clientJSON(target)
+	if err != nil {
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+
+	err = r.inTx(ctx, func(tx *sql.Tx) error {
+		merge = domain.ClientMerge{
+			PhotographerID: target.PhotographerID,
+			TargetID:       target.ID,
+			SourceID:       source.ID,
+			SourceName:     source.Name,
+		}
+
+		if err := lockMergedClients(ctx, tx, target, source); err != nil {
+			return err
+		}
+
+		for _, move := range []struct {
+			table string
+			count *int
+		}{
+			{"debts", &merge.Debts},
+			{"payments", &merge.Payments},
+			{"sessions", &merge.Sessions},
+			{"invoices", &merge.Invoices},
+		} {
+			res, err := tx.ExecContext(ctx, fmt.Sprintf(`update %s set client_id = $1 where client_id = $2`, move.table),
+				target.ID, source.ID)
+			if err != nil {
+				return fmt.Errorf("failed to move %s: %w", move.table, translateError(err))
+			}
+			moved, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to move %s: %w", move.table, err)
+			}
+			*move.count = int(moved)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			update clients
+			set contacts = $2, birthday = $3, source = $4, notes = $5, custom_fields = $6, updated_at = now()
+			where id = $1
+		`, target.ID, contacts, dateValue(target.Birthday), target.Source, target.Notes, customFields)
+		if err != nil {
+			return fmt.Errorf("failed to update client: %w", translateError(err))
+		}
+
+		_, err = tx.ExecContext(ctx, `update clients set deleted_at = now(), updated_at = now() where id = $1`,
+			source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete client: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			insert into client_merges (photographer_id, target_id, source_id, source_name, debts, payments, sessions, invoices)
+			values ($1, $2, $3, $4, $5, $6, $7, $8)
+			returning id, merged_at
+		`, merge.PhotographerID, merge.TargetID, merge.SourceID, merge.SourceName, merge.Debts, merge.Payments,
+			merge.Sessions, merge.Invoices).Scan(&merge.ID, &merge.MergedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save client merge: %w", translateError(err))
+		}
+
+		balance, err = getBalance(ctx, tx, target.ID)
+		return err
+	})
+	if err != nil {
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+
+	return merge, balance, nil
+}
+
+// lockMergedClients блокирует обоих клиентов в порядке ID, чтобы встречные
+// объединения не взаимоблокировались, и проверяет, что их не изменили.
+func lockMergedClients(ctx context.Context, tx *sql.Tx, target, source domain.Client) error {
+	rows, err := tx.QueryContext(ctx, `
+		select id, updated_at, deleted_at is not null
+		from clients
+		where id in ($1, $2)
+		order by id
+		for update
+	`, target.ID, source.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lock clients: %w", err)
+	}
+	defer rows.Close()
+
+	expected := map[domain.ClientID]domain.Client{target.ID: target, source.ID: source}
+	locked := 0
+	for rows.Next() {
+		var (
+			client  domain.Client
+			deleted bool
+		)
+		if err = rows.Scan(&client.ID, &client.UpdatedAt, &deleted); err != nil {
+			return fmt.Errorf("failed to lock clients: %w", err)
+		}
+		if deleted {
+			return domain.NewError(domain.ErrConflict, "client %d is deleted", client.ID)
+		}
+		if !client.UpdatedAt.Equal(expected[client.ID].UpdatedAt) {
+			return domain.NewError(domain.ErrConflict, "client %d was changed during merge, try again", client.ID)
+		}
+		locked++
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to lock clients: %w", err)
+	}
+	if locked != len(expected) {
+		return domain.NewError(domain.ErrNotFound, "client not found")
+	}
+
+	return nil
+}
+
+// GetClientMerges возвращает историю объединений, в которых клиент поглотил
+// другие карточки, от новых к старым.
+func (r *Repository) GetClientMerges(ctx context.Context, clientID domain.ClientID) ([]domain.ClientMerge, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		select id, photographer_id, target_id, source_id, source_name, debts, payments, sessions, invoices, merged_at
+		from client_merges
+		where target_id = $1
+		order by merged_at desc, id desc
+	`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client merges: %w", err)
+	}
+	defer rows.Close()
+
+	var merges []domain.ClientMerge
+	for rows.Next() {
+		var m domain.ClientMerge
+		err = rows.Scan(&m.ID, &m.PhotographerID, &m.TargetID, &m.SourceID, &m.SourceName, &m.Debts, &m.Payments,
+			&m.Sessions, &m.Invoices, &m.MergedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan client merge: %w", err)
+		}
+		merges = append(merges, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan client merge: %w", err)
+	}
+
+	return merges, nil
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+const packageColumns = `
+	id, photographer_id, name, description, duration_minutes, price, currency, deliverables,
+	archived_at, created_at, updated_at
+`
+
+func (r *Repository) CreatePackage(ctx context.Context, pkg domain.Package) (domain.PackageID, error) {
+	query := `
+		insert into packages (photographer_id, name, description, duration_minutes, price, currency, deliverables)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning id
+	`
+
+	var id domain.PackageID
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, pkg.PhotographerID, pkg.Name, pkg.Description, pkg.DurationMinutes,
+			pkg.Price.Amount, pkg.Price.Currency, pq.Array(pkg.Deliverables)).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to create package: %w", translateError(err))
+		}
+
+		return insertPackageAddons(ctx, tx, id, pkg.Addons)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// UpdatePackage заменяет описание, цену и опции пакета. Опции обновляются по ID,
+// новые добавляются, а пропавшие из списка архивируются, чтобы строки
+// проведённых начислений продолжали на них ссылаться. Начисления, сделанные по
+// пакету раньше, остаются со своими суммами.
+func (r *Repository) UpdatePackage(ctx context.Context, pkg domain.Package) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			update packages
+			set name = $2, description = $3, duration_minutes = $4, price = $5, currency = $6, deliverables = $7,
+			    updated_at = now()
+			where id = $1 and archived_at is null
+		`, pkg.ID, pkg.Name, pkg.Description, pkg.DurationMinutes, pkg.Price.Amount, pkg.Price.Currency,
+			pq.Array(pkg.Deliverables))
+		if err != nil {
+			return fmt.Errorf("failed to update package: %w", translateError(err))
+		}
+		if err = checkAffected(res, "package %d not found or archived", pkg.ID); err != nil {
+			return err
+		}
+
+		kept := make([]domain.AddonID, 0, len(pkg.Addons))
+		for _, addon := range pkg.Addons {
+			if addon.ID != 0 {
+				kept = append(kept, addon.ID)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			update package_addons
+			set archived_at = now()
+			where package_id = $1 and archived_at is null and not (id = any($2))
+		`, pkg.ID, pq.Array(kept))
+		if err != nil {
+			return fmt.Errorf("failed to archive package addons: %w", err)
+		}
+
+		return insertPackageAddons(ctx, tx, pkg.ID, pkg.Addons)
+	})
+}
+
+// ArchivePackage убирает пакет из прайс-листа. Пакет не удаляется: на него
+// ссылаются начисления.
+func (r *Repository) ArchivePackage(ctx context.Context, id domain.PackageID) error {
+	query := `update packages set archived_at = now() where id = $1 and archived_at is null`
+
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to archive package: %w", err)
+	}
+
+	return checkAffected(res, "package %d not found or archived", id)
+}
+
+func (r *Repository) GetPackage(ctx context.Context, id domain.PackageID) (domain.Package, error) {
+	query := fmt.Sprintf(`select %s from packages where id = $1`, packageColumns)
+
+	var pkg domain.Package
+	err := r.db.QueryRowContext(ctx, query, id).Scan(scanPackage(&pkg)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Package{}, domain.NewError(domain.ErrNotFound, "package %d not found", id)
+	}
+	if err != nil {
+		return domain.Package{}, fmt.Errorf("failed to get package: %w", err)
+	}
+
+	addons, err := r.getPackageAddons(ctx, []domain.PackageID{id})
+	if err != nil {
+		return domain.Package{}, err
+	}
+	pkg.Addons = withCurrency(addons[id], pkg.Price.Currency)
+
+	return pkg, nil
+}
+
+// GetPackages возвращает прайс-лист фотографа по названию пакетов. Прайс-лист
+// невелик, поэтому отдаётся целиком, без страниц.
+func (r *Repository) GetPackages(ctx context.Context, photographerID domain.PhotographerID, includeArchived bool) ([]domain.Package, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from packages
+		where photographer_id = $1 and ($2 or archived_at is null)
+		order by lower(name), id
+	`, packageColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, photographerID, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get packages: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		packages []domain.Package
+		ids      []domain.PackageID
+	)
+	for rows.Next() {
+		var pkg domain.Package
+		if err = rows.Scan(scanPackage(&pkg)...); err != nil {
+			return nil, fmt.Errorf("failed to scan package: %w", err)
+		}
+		packages = append(packages, pkg)
+		ids = append(ids, pkg.ID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan package: %w", err)
+	}
+
+	addons, err := r.getPackageAddons(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, pkg := range packages {
+		packages[i].Addons = withCurrency(addons[pkg.ID], pkg.Price.Currency)
+	}
+
+	return packages, nil
+}
+
+func (r *Repository) getPackageAddons(ctx context.Context, ids []domain.PackageID) (map[domain.PackageID][]domain.Addon, error) {
+	addons := make(map[domain.PackageID][]domain.Addon, len(ids))
+	if len(ids) == 0 {
+		return addons, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		select package_id, id, name, price
+		from package_addons
+		where package_id = any($1) and archived_at is null
+		order by package_id, position
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package addons: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			packageID domain.PackageID
+			addon     domain.Addon
+		)
+		if err = rows.Scan(&packageID, &addon.ID, &addon.Name, &addon.Price.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan package addon: %w", err)
+		}
+		addons[packageID] = append(addons[packageID], addon)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan package addon: %w", err)
+	}
+
+	return addons, nil
+}
+
+// insertPackageAddons добавляет опции без ID и обновляет на месте опции с ID,
+// расставляя их в порядке списка.
+func insertPackageAddons(ctx context.Context, tx *sql.Tx, id domain.PackageID, addons []domain.Addon) error {
+	for i, addon := range addons {
+		if addon.ID == 0 {
+			_, err := tx.ExecContext(ctx, `
+				insert into package_addons (package_id, position, name, price)
+				values ($1, $2, $3, $4)
+			`, id, i, addon.Name, addon.Price.Amount)
+			if err != nil {
+				return fmt.Errorf("failed to add package addon: %w", translateError(err))
+			}
+			continue
+		}
+
+		res, err := tx.ExecContext(ctx, `
+			update package_addons
+			set position = $3, name = $4, price = $5
+			where id = $1 and package_id = $2 and archived_at is null
+		`, addon.ID, id, i, addon.Name, addon.Price.Amount)
+		if err != nil {
+			return fmt.Errorf("failed to update package addon: %w", translateError(err))
+		}
+		if err = checkAffected(res, "package %d has no addon %d", id, addon.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withCurrency проставляет опциям валюту пакета: в базе она хранится только у пакета.
+func withCurrency(addons []domain.Addon, currency string) []domain.Addon {
+	if addons == nil {
+		return []domain.Addon{}
+	}
+	for i := range addons {
+		addons[i].Price.Currency = currency
+	}
+	return addons
+}
+
+func scanPackage(p *domain.Package) []any {
+	return []any{&p.ID, &p.PhotographerID, &p.Name, &p.Description, &p.DurationMinutes, &p.Price.Amount,
+		&p.Price.Currency, pq.Array(&p.Deliverables), &p.ArchivedAt, &p.CreatedAt, &p.UpdatedAt}
+}
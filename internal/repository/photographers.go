@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+)
+
+const photographerColumns = `id, name, coalesce(login, '') as login, display_name, legal_name, tax_id, email, phone,
+	time_zone, currency, created_at, updated_at, deactivated_at`
+
+// scanPhotographer возвращает адреса полей фотографа в порядке photographerColumns.
+func scanPhotographer(p *domain.Photographer) []any {
+	return []any{&p.ID, &p.Name, &p.Login, &p.DisplayName, &p.LegalName, &p.TaxID, &p.Email, &p.Phone,
+		&p.TimeZone, &p.Currency, &p.CreatedAt, &p.UpdatedAt, &p.DeactivatedAt}
+}
+
+// UpdatePhotographer сохраняет профиль фотографа. Валюту можно сменить, только
+// пока у фотографа нет ни одной суммы: пересчитывать записанные суммы нельзя.
+func (r *Repository) UpdatePhotographer(ctx context.Context, photographer domain.Photographer) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		var currency string
+		err := tx.QueryRowContext(ctx, `select currency from photographers where id = $1 for update`,
+			photographer.ID).Scan(&currency)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.NewError(domain.ErrNotFound, "photographer %d not found", photographer.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock photographer %d: %w", photographer.ID, err)
+		}
+
+		if currency != photographer.Currency {
+			var hasMoney bool
+			err = tx.QueryRowContext(ctx, `
+				select exists (select 1 from debts where photographer_id = $1)
+				    or exists (select 1 from payments where photographer_id = $1)
+				    or exists (select 1 from invoices where photographer_id = $1)
+				    or exists (select 1 from sessions where photographer_id = $1)
+				    or exists (select 1 from packages where photographer_id = $1)
+			`, photographer.ID).Scan(&hasMoney)
+			if err != nil {
+				return fmt.Errorf("failed to check photographer money: %w", err)
+			}
+			if hasMoney {
+				return domain.NewError(domain.ErrConflict,
+					"currency of photographer %d cannot be changed: there are amounts in %s", photographer.ID, currency)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			update photographers
+			set name = $2, display_name = $3, legal_name = $4, tax_id = $5, email = $6, phone = $7,
+			    time_zone = $8, currency = $9, updated_at = now()
+			where id = $1
+		`, photographer.ID, photographer.Name, photographer.DisplayName, photographer.LegalName, photographer.TaxID,
+			photographer.Email, photographer.Phone, photographer.TimeZone, photographer.Currency)
+		if err != nil {
+			return fmt.Errorf("failed to update photographer: %w", translateError(err))
+		}
+
+		return nil
+	})
+}
+
+// DeactivatePhotographer запрещает фотографу вносить изменения. Записи и токены
+// доступа остаются: фотограф может читать свою историю.
+func (r *Repository) DeactivatePhotographer(ctx context.Context, id domain.PhotographerID) error {
+	res, err := r.db.ExecContext(ctx, `
+		update photographers
+		set deactivated_at = now(), updated_at = now()
+		where id = $1 and deactivated_at is null
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate photographer: %w", err)
+	}
+
+	return checkAffected(res, "photographer %d not found or already deactivated", id)
+}
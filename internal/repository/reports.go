@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"photographer/internal/domain"
+	"strings"
+	"time"
+)
+
+// GetIncomeTotals считает оплаты за период [From, To) по клиентам и в целом, а
+// также за предыдущий период той же длины. Первой строкой идёт общий итог.
+// Фильтры по клиенту и удалённым клиентам применяются так же, как в GetPayments.
+func (r *Repository) GetIncomeTotals(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.IncomeTotal, []domain.IncomeTotal, error) {
+	from, to := params.From, params.To
+	params.From, params.To = nil, nil
+	where, args := paymentsList.filters([]any{photographerID}, params)
+
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where = append(where,
+		"(b.previous_from is null or t.occurred_at >= b.previous_from)",
+		"t.occurred_at < b.to_at")
+
+	query := fmt.Sprintf(`
+		with period as (
+			select %s::timestamptz as from_at, coalesce(%s::timestamptz, now()) as to_at
+		), bounds as (
+			select from_at, to_at, from_at - (to_at - from_at) as previous_from
+			from period
+		), totals as (
+			select grouping(t.client_id) = 1 as is_total, t.client_id, t.client_name,
+			       coalesce(sum(t.amount) filter (where b.from_at is null or t.occurred_at >= b.from_at), 0) as amount,
+			       count(*) filter (where t.counted and (b.from_at is null or t.occurred_at >= b.from_at)) as cnt,
+			       coalesce(sum(t.amount) filter (where t.occurred_at < b.from_at), 0) as previous_amount,
+			       count(*) filter (where t.counted and t.occurred_at < b.from_at) as previous_count
+			from (%s) t
+			cross join bounds b
+			where %s
+			group by grouping sets ((t.client_id, t.client_name), ())
+		)
+		select coalesce(tt.client_id, 0), coalesce(tt.client_name, ''), tt.amount, tt.cnt,
+		       case when b.from_at is not null then tt.previous_amount end, tt.previous_count,
+		       round(100.0 * (tt.amount - tt.previous_amount) / nullif(tt.previous_amount, 0), 2),
+		       ph.currency
+		from totals tt
+		cross join bounds b
+		join photographers ph on ph.id = $1
+		order by tt.is_total desc, tt.amount desc, tt.client_id
+	`, arg(from), arg(to), paymentsBase, strings.Join(where, " and "))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.IncomeTotal{}, nil, fmt.Errorf("failed to get income totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []domain.IncomeTotal
+	for rows.Next() {
+		var (
+			total    domain.IncomeTotal
+			previous sql.NullInt64
+			change   sql.NullFloat64
+		)
+		if err = rows.Scan(&total.ClientID, &total.ClientName, &total.Amount.Amount, &total.Count,
+			&previous, &total.PreviousCount, &change, &total.Amount.Currency); err != nil {
+			return domain.IncomeTotal{}, nil, fmt.Errorf("failed to scan income total: %w", err)
+		}
+		if previous.Valid {
+			money := domain.NewMoney(previous.Int64, total.Amount.Currency)
+			total.Previous = &money
+		}
+		if change.Valid {
+			total.ChangePercent = &change.Float64
+		}
+		totals = append(totals, total)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.IncomeTotal{}, nil, fmt.Errorf("failed to scan income total: %w", err)
+	}
+
+	if len(totals) == 0 {
+		return domain.IncomeTotal{}, nil, domain.NewError(domain.ErrNotFound, "photographer %d not found", photographerID)
+	}
+
+	return totals[0], totals[1:], nil
+}
+
+// GetFirstIncomeAt возвращает время первой оплаты, попадающей под фильтры
+// params, или nil, если таких оплат нет. Так отчёт без From узнаёт, с какого
+// момента начнутся его интервалы.
+func (r *Repository) GetFirstIncomeAt(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (*time.Time, error) {
+	params.From = nil
+	where, args := paymentsList.filters([]any{photographerID}, params)
+
+	filter := ""
+	if len(where) > 0 {
+		filter = "where " + strings.Join(where, " and ")
+	}
+
+	var first sql.NullTime
+	err := r.db.QueryRowContext(ctx, fmt.Sprintf(`select min(t.occurred_at) from (%s) t %s`, paymentsBase, filter),
+		args...).Scan(&first)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get first income: %w", err)
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+
+	return &first.Time, nil
+}
+
+// GetIncomeBuckets разбивает оплаты за период на интервалы groupBy (day, week,
+// month, year) в часовом поясе фотографа. Интервалы без оплат тоже попадают в
+// отчёт. Без From период начинается с первой оплаты, без To — заканчивается сейчас.
+func (r *Repository) GetIncomeBuckets(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams, groupBy string) ([]domain.IncomeBucket, error) {
+	where, args := paymentsList.filters([]any{photographerID}, params)
+
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	filter := ""
+	if len(where) > 0 {
+		filter = "where " + strings.Join(where, " and ")
+	}
+
+	groupByArg, fromArg, toArg := arg(groupBy), arg(params.From), arg(params.To)
+	query := fmt.Sprintf(`
+		with settings as (
+			select time_zone as tz, currency
+			from photographers
+			where id = $1
+		), filtered as (
+			select t.amount, t.occurred_at, t.counted
+			from (%s) t
+			%s
+		), series as (
+			select generate_series(
+				date_trunc(%[3]s::text, coalesce(%[4]s::timestamptz, (select min(occurred_at) from filtered)) at time zone s.tz),
+				(coalesce(%[5]s::timestamptz, now()) at time zone s.tz) - interval '1 microsecond',
+				('1 ' || %[3]s::text)::interval
+			) as bucket
+			from settings s
+		)
+		select sr.bucket at time zone s.tz, coalesce(sum(f.amount), 0), count(*) filter (where f.counted), s.currency
+		from series sr
+		cross join settings s
+		left join filtered f on date_trunc(%[3]s::text, f.occurred_at at time zone s.tz) = sr.bucket
+		group by sr.bucket, s.tz, s.currency
+		order by sr.bucket
+	`, paymentsBase, filter, groupByArg, fromArg, toArg)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get income buckets: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []domain.IncomeBucket{}
+	for rows.Next() {
+		var bucket domain.IncomeBucket
+		if err = rows.Scan(&bucket.Start, &bucket.Amount.Amount, &bucket.Count, &bucket.Amount.Currency); err != nil {
+			return nil, fmt.Errorf("failed to scan income bucket: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan income bucket: %w", err)
+	}
+
+	return buckets, nil
+}
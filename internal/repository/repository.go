@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	_ "github.com/lib/pq"
 	"photographer/internal/domain"
+	"strings"
 )
 
 type Repository struct {
@@ -16,276 +18,599 @@ func New(db *sql.DB) *Repository {
 	return &Repository{db}
 }
 
-func (r *Repository) CreatePhotographer(ctx context.Context, name string) (domain.PhotographerID, error) {
+func (r *Repository) CreatePhotographer(ctx context.Context, photographer domain.Photographer, passwordHash string) (domain.PhotographerID, error) {
 	query := `
-		insert into photographers (name)
-		values ($1)
+		insert into photographers (name, login, password_hash, time_zone, currency)
+		values ($1, $2, $3, $4, $5)
 		returning id
 	`
 
 	var id domain.PhotographerID
-	err := r.db.QueryRowContext(ctx, query, name).Scan(&id)
+	err := r.db.QueryRowContext(ctx, query, photographer.Name, photographer.Login, passwordHash,
+		photographer.TimeZone, photographer.Currency).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create photographer: %w", err)
+		return 0, fmt.Errorf("failed to create photographer: %w", translateError(err))
 	}
 
 	return id, nil
 }
 
-func (r *Repository) GetPhotographers(ctx context.Context) ([]domain.Photographer, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, name, created_at at time zone 'Europe/Moscow' FROM photographers")
+var photographersList = listSpec{
+	sorts: map[string]sortColumn{
+		"name": {column: "display_name", cast: "text"},
+		"date": {column: "created_at", cast: "timestamptz"},
+	},
+	defaultSort: "name",
+	dateColumn:  "created_at",
+}
+
+// GetPhotographers возвращает только публичные сведения о фотографах: реквизиты,
+// контакты и логин видны лишь самому фотографу в его профиле.
+func (r *Repository) GetPhotographers(ctx context.Context, params domain.ListParams) (domain.Page[domain.PhotographerSummary], error) {
+	base := `select id, coalesce(nullif(display_name, ''), name) as display_name, created_at from photographers`
+
+	query, args, err := photographersList.build(base, "t.id, t.display_name", nil, &params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get photographers: %w", err)
+		return domain.Page[domain.PhotographerSummary]{}, err
 	}
 
-	var photographers []domain.Photographer
-	for rows.Next() {
-		var photographer domain.Photographer
-		if err = rows.Scan(&photographer.ID, &photographer.Name, &photographer.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan photographer: %w", err)
-		}
-		photographers = append(photographers, photographer)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.Page[domain.PhotographerSummary]{}, fmt.Errorf("failed to get photographers: %w", err)
+	}
+
+	page, err := scanPage(rows, params,
+		func(p *domain.PhotographerSummary) []any { return []any{&p.ID, &p.DisplayName} },
+		func(p domain.PhotographerSummary) int64 { return int64(p.ID) })
+	if err != nil {
+		return domain.Page[domain.PhotographerSummary]{}, fmt.Errorf("failed to scan photographer: %w", err)
 	}
 
-	return photographers, nil
+	return page, nil
 }
 
-func (r *Repository) CreateClient(ctx context.Context, photographerID domain.PhotographerID, name string) (domain.ClientID, error) {
+func (r *Repository) GetPhotographer(ctx context.Context, id domain.PhotographerID) (domain.Photographer, error) {
+	query := `select ` + photographerColumns + ` from photographers where id = $1`
+
+	var photographer domain.Photographer
+	err := r.db.QueryRowContext(ctx, query, id).Scan(scanPhotographer(&photographer)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Photographer{}, domain.NewError(domain.ErrNotFound, "photographer %d not found", id)
+	}
+	if err != nil {
+		return domain.Photographer{}, fmt.Errorf("failed to get photographer: %w", err)
+	}
+
+	return photographer, nil
+}
+
+func (r *Repository) CreateClient(ctx context.Context, client domain.Client) (domain.ClientID, error) {
 	query := `
-		insert into clients (photographer_id, name)
-		values ($1, $2)
+		insert into clients (photographer_id, name, contacts, birthday, source, notes, custom_fields)
+		values ($1, $2, $3, $4, $5, $6, $7)
 		returning id
 	`
 
+	contacts, customFields, err := clientJSON(client)
+	if err != nil {
+		return 0, err
+	}
+
 	var id domain.ClientID
-	err := r.db.QueryRowContext(ctx, query, photographerID, name).Scan(&id)
+	err = r.db.QueryRowContext(ctx, query, client.PhotographerID, client.Name, contacts, dateValue(client.Birthday),
+		client.Source, client.Notes, customFields).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create client: %w", err)
+		return 0, fmt.Errorf("failed to create client: %w", translateError(err))
 	}
 
 	return id, nil
 }
 
-func (r *Repository) UpdateClient(ctx context.Context, id domain.ClientID, name string) error {
-	query := `update clients set name = $1, updated_at = now() where id = $2`
+// UpdateClient записывает карточку клиента целиком.
+func (r *Repository) UpdateClient(ctx context.Context, client domain.Client) error {
+	query := `
+		update clients
+		set name = $3, contacts = $4, birthday = $5, source = $6, notes = $7, custom_fields = $8, updated_at = now()
+		where id = $1 and photographer_id = $2
+	`
 
-	if _, err := r.db.ExecContext(ctx, query, name, id); err != nil {
-		return fmt.Errorf("failed to update client: %w", err)
+	contacts, customFields, err := clientJSON(client)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	res, err := r.db.ExecContext(ctx, query, client.ID, client.PhotographerID, client.Name, contacts,
+		dateValue(client.Birthday), client.Source, client.Notes, customFields)
+	if err != nil {
+		return fmt.Errorf("failed to update client: %w", translateError(err))
+	}
+
+	return checkAffected(res, "client %d not found", client.ID)
 }
 
-func (r *Repository) DeleteClient(ctx context.Context, id domain.ClientID) error {
-	query := `update clients set deleted_at = now() where id = $1`
+// DeleteClient переносит клиента в корзину. Повторное удаление не сдвигает
+// срок хранения в корзине.
+func (r *Repository) DeleteClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error {
+	query := `update clients set deleted_at = now() where id = $1 and photographer_id = $2 and deleted_at is null`
 
-	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
-		return fmt.Errorf("failed to delete client: %w", err)
+	res, err := r.db.ExecContext(ctx, query, id, photographerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete client: %w", translateError(err))
 	}
 
-	return nil
+	return checkAffected(res, "client %d not found or already deleted", id)
+}
+
+var clientsList = listSpec{
+	sorts: map[string]sortColumn{
+		"name":   {column: "name", cast: "text"},
+		"amount": {column: "balance", cast: "numeric"},
+		"date":   {column: "created_at", cast: "timestamptz"},
+	},
+	defaultSort:   "name",
+	dateColumn:    "created_at",
+	amountColumn:  "balance",
+	deletedColumn: "deleted_at",
+}
+
+const clientsBase = `
+	select c.id, c.photographer_id, c.name, c.contacts, c.birthday, c.source, c.notes, c.custom_fields,
+	       c.created_at, c.updated_at, c.deleted_at,
+	       coalesce(d.total, 0) - coalesce(p.total, 0) as balance, ph.currency
+	from clients c
+	join photographers ph on ph.id = c.photographer_id
+	left join (
+		select client_id, sum(amount) as total
+		from debts
+		where photographer_id = $1
+		group by client_id
+	) d on d.client_id = c.id
+	left join (
+		select client_id, sum(amount) as total
+		from payments
+		where photographer_id = $1
+		group by client_id
+	) p on p.client_id = c.id
+	where c.photographer_id = $1
+`
+
+func (r *Repository) GetClients(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error) {
+	return r.getClients(ctx, clientsList, clientsBase, photographerID, params)
+}
+
+func (r *Repository) getClients(ctx context.Context, spec listSpec, base string, photographerID domain.PhotographerID,
+	params domain.ListParams) (domain.Page[domain.Client], error) {
+	query, args, err := spec.build(base,
+		"t.id, t.photographer_id, t.name, t.contacts, t.birthday, t.source, t.notes, t.custom_fields, "+
+			"t.created_at, t.updated_at, t.deleted_at, t.balance, t.currency",
+		[]any{photographerID}, &params)
+	if err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.Page[domain.Client]{}, fmt.Errorf("failed to get clients: %w", err)
+	}
+
+	// баланс читается в Debt и затем раскладывается на долг и кредит
+	page, err := scanPage(rows, params, func(c *domain.Client) []any {
+		return append(scanClient(c), &c.Balance.Debt.Amount, &c.Balance.Debt.Currency)
+	}, func(c domain.Client) int64 { return int64(c.ID) })
+	if err != nil {
+		return domain.Page[domain.Client]{}, fmt.Errorf("failed to scan client: %w", err)
+	}
+
+	for i := range page.Items {
+		page.Items[i].Balance = domain.NewBalance(page.Items[i].Balance.Debt)
+	}
+
+	return page, nil
 }
 
-func (r *Repository) GetClients(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Client, error) {
+func (r *Repository) GetClient(ctx context.Context, id domain.ClientID) (domain.Client, error) {
 	query := `
-		select id, photographer_id, name, 
-		       created_at at time zone 'Europe/Moscow', 
-		       updated_at at time zone 'Europe/Moscow', 
-		       deleted_at at time zone 'Europe/Moscow'
+		select id, photographer_id, name, contacts, birthday, source, notes, custom_fields,
+		       created_at, updated_at, deleted_at
 		from clients
-		where photographer_id = $1
+		where id = $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, photographerID)
+	var client domain.Client
+	err := r.db.QueryRowContext(ctx, query, id).Scan(scanClient(&client)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Client{}, domain.NewError(domain.ErrNotFound, "client %d not found", id)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get clients: %w", err)
+		return domain.Client{}, fmt.Errorf("failed to get client: %w", err)
 	}
 
-	var clients []domain.Client
-	for rows.Next() {
-		var client domain.Client
-		if err = rows.Scan(&client.ID, &client.PhotographerID, &client.Name,
-			&client.CreatedAt, &client.UpdatedAt, &client.DeletedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan client: %w", err)
+	if client.Balance, err = getBalance(ctx, r.db, id); err != nil {
+		return domain.Client{}, err
+	}
+
+	return client, nil
+}
+
+func (r *Repository) GetClientBalance(ctx context.Context, clientID domain.ClientID) (domain.Balance, error) {
+	return getBalance(ctx, r.db, clientID)
+}
+
+func (r *Repository) AddDebt(ctx context.Context, debt domain.DebtEntry) (domain.DebtID, domain.Balance, error) {
+	query := `
+		insert into debts (photographer_id, client_id, amount, currency, description, due_date, package_id)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning id
+	`
+
+	var (
+		id      domain.DebtID
+		balance domain.Balance
+	)
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := lockActiveClient(ctx, tx, debt.ClientID); err != nil {
+			return err
 		}
-		clients = append(clients, client)
+
+		err := tx.QueryRowContext(ctx, query,
+			debt.PhotographerID, debt.ClientID, debt.Amount.Amount, debt.Amount.Currency,
+			debt.Description, debt.DueDate, debt.PackageID).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("failed to add debt: %w", translateError(err))
+		}
+
+		for i, line := range debt.Lines {
+			_, err = tx.ExecContext(ctx, `
+				insert into debt_lines (debt_id, position, addon_id, name, quantity, unit_price)
+				values ($1, $2, $3, $4, $5, $6)
+			`, id, i, line.AddonID, line.Name, line.Quantity, line.UnitPrice.Amount)
+			if err != nil {
+				return fmt.Errorf("failed to add debt line: %w", translateError(err))
+			}
+		}
+
+		balance, err = getBalance(ctx, tx, debt.ClientID)
+		return err
+	})
+	if err != nil {
+		return 0, domain.Balance{}, err
 	}
 
-	return clients, nil
+	return id, balance, nil
 }
 
-func (r *Repository) AddDebt(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error {
-	tx, err := r.db.Begin()
+var debtorsList = listSpec{
+	sorts: map[string]sortColumn{
+		"name":   {column: "name", cast: "text"},
+		"amount": {column: "balance", cast: "numeric"},
+		"date":   {column: "occurred_at", cast: "timestamptz"},
+	},
+	defaultSort:   "-amount",
+	dateColumn:    "occurred_at",
+	amountColumn:  "balance",
+	clientColumn:  "id",
+	deletedColumn: "deleted_at",
+}
+
+func (r *Repository) GetDebts(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Debt], error) {
+	base := `
+		with movements as (
+			select client_id, amount, occurred_at as charged_at, null::timestamptz as paid_at
+			from debts
+			where photographer_id = $1
+			union all
+			select client_id, -amount, null, occurred_at
+			from payments
+			where photographer_id = $1
+		)
+		select c.id, c.name, c.deleted_at, sum(m.amount) as balance, ph.currency,
+		       coalesce(max(m.charged_at), max(m.paid_at)) as occurred_at
+		from movements m
+		join clients c on c.id = m.client_id
+		join photographers ph on ph.id = c.photographer_id
+		group by c.id, c.name, c.deleted_at, ph.currency
+		having sum(m.amount) <> 0
+	`
+
+	query, args, err := debtorsList.build(base, "t.id, t.name, t.balance, t.currency, t.occurred_at",
+		[]any{photographerID}, &params)
 	if err != nil {
-		return fmt.Errorf("AddDebt: failed to start transaction: %w", err)
+		return domain.Page[domain.Debt]{}, err
 	}
 
-	defer func() {
-		_ = tx.Rollback()
-	}()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.Page[domain.Debt]{}, fmt.Errorf("failed to get debts: %w", err)
+	}
 
-	currentDebt, err := getDebt(ctx, tx, photographerID, clientID)
+	// остаток читается в Amount и затем раскладывается на долг и кредит
+	page, err := scanPage(rows, params, func(d *domain.Debt) []any {
+		return []any{&d.ClientID, &d.ClientName, &d.Amount.Amount, &d.Amount.Currency, &d.OccurredAt}
+	}, func(d domain.Debt) int64 { return int64(d.ClientID) })
 	if err != nil {
-		return err
+		return domain.Page[domain.Debt]{}, fmt.Errorf("failed to scan debts: %w", err)
 	}
 
-	if err = addDebt(ctx, tx, photographerID, clientID, currentDebt+amount); err != nil {
-		return err
+	for i := range page.Items {
+		b := domain.NewBalance(page.Items[i].Amount)
+		page.Items[i].Amount, page.Items[i].Credit = b.Debt, b.Credit
 	}
 
-	return tx.Commit()
+	return page, nil
 }
 
-func (r *Repository) GetDebts(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Debt, error) {
+func (r *Repository) GetClientDebts(ctx context.Context, clientID domain.ClientID) ([]domain.DebtEntry, error) {
 	query := `
-		select client_id, c.name, amount, occurred_at at time zone 'Europe/Moscow'
+		select id, photographer_id, client_id, kind, amount, currency, reversal_of, description, due_date, package_id, occurred_at
 		from debts
-		join public.clients c on debts.client_id = c.id
-		where debts.photographer_id = $1
+		where client_id = $1
+		order by occurred_at, id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, photographerID)
+	rows, err := r.db.QueryContext(ctx, query, clientID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get debts: %w", err)
+		return nil, fmt.Errorf("failed to get client debts: %w", err)
 	}
+	defer rows.Close()
 
-	var debts []domain.Debt
+	var debts []domain.DebtEntry
 	for rows.Next() {
-		var debt domain.Debt
-		if err = rows.Scan(&debt.ClientID, &debt.ClientName, &debt.Amount, &debt.OccurredAt); err != nil {
-			return nil, fmt.Errorf("failed to scan debts: %w", err)
+		var debt domain.DebtEntry
+		if err = rows.Scan(&debt.ID, &debt.PhotographerID, &debt.ClientID, &debt.Kind, &debt.Amount.Amount,
+			&debt.Amount.Currency, &debt.ReversalOf, &debt.Description, &debt.DueDate, &debt.PackageID, &debt.OccurredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan client debt: %w", err)
 		}
 		debts = append(debts, debt)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan client debt: %w", err)
+	}
+
+	if err = r.addDebtLines(ctx, clientID, debts); err != nil {
+		return nil, err
+	}
 
 	return debts, nil
 }
 
-func (r *Repository) AddPayment(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error {
-	tx, err := r.db.Begin()
+// addDebtLines дополняет начисления клиента строками начислений по пакетам.
+func (r *Repository) addDebtLines(ctx context.Context, clientID domain.ClientID, debts []domain.DebtEntry) error {
+	rows, err := r.db.QueryContext(ctx, `
+		select l.debt_id, l.addon_id, l.name, l.quantity, l.unit_price, d.currency
+		from debt_lines l
+		join debts d on d.id = l.debt_id
+		where d.client_id = $1
+		order by l.debt_id, l.position
+	`, clientID)
 	if err != nil {
-		return fmt.Errorf("AddPayment: failed to start transaction: %w", err)
+		return fmt.Errorf("failed to get debt lines: %w", err)
 	}
+	defer rows.Close()
 
-	defer func() {
-		_ = tx.Rollback()
-	}()
+	index := make(map[domain.DebtID]int, len(debts))
+	for i, debt := range debts {
+		index[debt.ID] = i
+	}
 
-	sumDebt, err := getDebt(ctx, tx, photographerID, clientID)
-	if err != nil {
-		return err
+	for rows.Next() {
+		var (
+			debtID   domain.DebtID
+			line     domain.DebtLine
+			currency string
+		)
+		if err = rows.Scan(&debtID, &line.AddonID, &line.Name, &line.Quantity, &line.UnitPrice.Amount, &currency); err != nil {
+			return fmt.Errorf("failed to scan debt line: %w", err)
+		}
+		line.UnitPrice.Currency = currency
+		line.Amount = domain.NewMoney(line.UnitPrice.Amount*int64(line.Quantity), currency)
+
+		if i, ok := index[debtID]; ok {
+			debts[i].Lines = append(debts[i].Lines, line)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to scan debt line: %w", err)
 	}
 
-	dif := sumDebt - amount
+	return nil
+}
 
-	if dif <= 0 {
-		if err = deleteDebt(ctx, tx, photographerID, clientID); err != nil {
+func (r *Repository) AddPayment(ctx context.Context, photographerID domain.PhotographerID, payment domain.Payment) (domain.Balance, error) {
+	query := `
+		insert into payments (photographer_id, client_id, amount, currency, method, reference, note, occurred_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8);
+	`
+
+	var balance domain.Balance
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		if err := lockActiveClient(ctx, tx, payment.ClientID); err != nil {
 			return err
 		}
-	} else {
-		if err = addDebt(ctx, tx, photographerID, clientID, dif); err != nil {
-			return err
+
+		_, err := tx.ExecContext(ctx, query, photographerID, payment.ClientID, payment.Amount.Amount, payment.Amount.Currency,
+			payment.Method, payment.Reference, payment.Note, payment.OccurredAt)
+		if err != nil {
+			return fmt.Errorf("failed to add payment: %w", translateError(err))
 		}
-	}
 
-	if err = addPayment(ctx, tx, photographerID, clientID, amount); err != nil {
+		balance, err = getBalance(ctx, tx, payment.ClientID)
 		return err
+	})
+	if err != nil {
+		return domain.Balance{}, err
 	}
 
-	return tx.Commit()
+	return balance, nil
 }
 
-func (r *Repository) GetPayments(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Payment, error) {
-	query := `
-		select client_id, amount, occurred_at at time zone 'Europe/Moscow'
-		from payments
-		where photographer_id = $1
-	`
+var paymentsList = listSpec{
+	sorts: map[string]sortColumn{
+		"amount": {column: "amount", cast: "bigint"},
+		"date":   {column: "occurred_at", cast: "timestamptz"},
+	},
+	defaultSort:   "-date",
+	dateColumn:    "occurred_at",
+	amountColumn:  "abs_amount",
+	clientColumn:  "client_id",
+	deletedColumn: "deleted_at",
+}
 
-	rows, err := r.db.QueryContext(ctx, query, photographerID)
+// paymentsBase — все движения по оплатам, включая возвраты и сторно: их сумма и
+// есть доход. counted отмечает оплаты, которые учитываются в числе оплат.
+// min_amount сравнивается с abs_amount, чтобы не терять возвраты и сторно с
+// отрицательной суммой.
+const paymentsBase = `
+	select p.id, p.client_id, c.name as client_name, p.kind, p.amount, abs(p.amount) as abs_amount, p.currency,
+	       p.method, p.reference, p.note, p.reversal_of, p.reason, p.voided_at, p.occurred_at, c.deleted_at,
+	       p.kind = 'payment' and p.voided_at is null as counted
+	from payments p
+	join clients c on c.id = p.client_id
+	where p.photographer_id = $1
+`
+
+func (r *Repository) GetPayments(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Payment], error) {
+	query, args, err := paymentsList.build(paymentsBase,
+		"t.id, t.client_id, t.kind, t.amount, t.currency, t.method, t.reference, t.note, t.reversal_of, t.reason, "+
+			"t.voided_at, t.occurred_at",
+		[]any{photographerID}, &params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get payments: %w", err)
+		return domain.Page[domain.Payment]{}, err
 	}
 
-	var payments []domain.Payment
-	for rows.Next() {
-		var payment domain.Payment
-		if err = rows.Scan(&payment.ClientID, &payment.Amount, &payment.OccurredAt); err != nil {
-			return nil, fmt.Errorf("failed to scan payment: %w", err)
-		}
-		payments = append(payments, payment)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.Page[domain.Payment]{}, fmt.Errorf("failed to get payments: %w", err)
 	}
 
-	return payments, nil
+	page, err := scanPage(rows, params, scanPayment, func(p domain.Payment) int64 { return int64(p.ID) })
+	if err != nil {
+		return domain.Page[domain.Payment]{}, fmt.Errorf("failed to scan payment: %w", err)
+	}
+
+	return page, nil
 }
 
-func (r *Repository) GetPaymentsTotal(ctx context.Context, photographerID domain.PhotographerID) (int, error) {
-	query := `
-		select coalesce(sum(amount),0)
-		from payments
-		where photographer_id = $1
-	`
+// GetPaymentsTotals считает сумму оплат с теми же фильтрами, что и GetPayments,
+// но без учёта страниц, в целом и по способам оплаты.
+func (r *Repository) GetPaymentsTotals(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.PaymentTotals, error) {
+	where, args := paymentsList.filters([]any{photographerID}, params)
 
-	var sum int
-	if err := r.db.QueryRowContext(ctx, query, photographerID).Scan(&sum); err != nil {
-		return 0, fmt.Errorf("failed to get payments total: %w", err)
+	join := "true"
+	if len(where) > 0 {
+		join = strings.Join(where, " and ")
 	}
 
-	return sum, nil
-}
+	// без оплат левое соединение даёт одну пустую строку: она остаётся только в общем итоге
+	query := fmt.Sprintf(`
+		select grouping(t.method) = 1, coalesce(t.method, ''), coalesce(sum(t.amount), 0),
+		       count(*) filter (where t.counted), ph.currency
+		from photographers ph
+		left join (%s) t on %s
+		where ph.id = $1
+		group by grouping sets ((ph.currency), (ph.currency, t.method))
+		having grouping(t.method) = 1 or t.method is not null
+		order by 1 desc, 2
+	`, paymentsBase, join)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.PaymentTotals{}, fmt.Errorf("failed to get payments totals: %w", err)
+	}
+	defer rows.Close()
 
-func addDebt(ctx context.Context, tx *sql.Tx, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error {
-	query := `
-		insert into debts (photographer_id, client_id, amount)
-		values ($1, $2, $3)
-		on conflict (photographer_id, client_id)
-		do update set amount = excluded.amount;
-	`
+	totals := domain.PaymentTotals{ByMethod: []domain.MethodTotal{}}
+	found := false
+	for rows.Next() {
+		var (
+			isTotal bool
+			total   domain.MethodTotal
+		)
+		if err = rows.Scan(&isTotal, &total.Method, &total.Amount.Amount, &total.Count, &total.Amount.Currency); err != nil {
+			return domain.PaymentTotals{}, fmt.Errorf("failed to scan payments total: %w", err)
+		}
+		if isTotal {
+			totals.Total, found = total.Amount, true
+			continue
+		}
+		totals.ByMethod = append(totals.ByMethod, total)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.PaymentTotals{}, fmt.Errorf("failed to scan payments total: %w", err)
+	}
 
-	if _, err := tx.ExecContext(ctx, query, photographerID, clientID, amount); err != nil {
-		return fmt.Errorf("failed to create debt: %w", err)
+	if !found {
+		return domain.PaymentTotals{}, domain.NewError(domain.ErrNotFound, "photographer %d not found", photographerID)
 	}
 
-	return nil
+	return totals, nil
 }
 
-func getDebt(ctx context.Context, tx *sql.Tx, photographerID domain.PhotographerID, clientID domain.ClientID) (int, error) {
-	query := `
-		select coalesce(sum(amount),0) from debts
-		where photographer_id = $1 and client_id = $2
-	`
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
 
-	var sumDebt int
-	err := tx.QueryRowContext(ctx, query, photographerID, clientID).Scan(&sumDebt)
+// lockClient берёт блокировку строки клиента до конца транзакции, чтобы денежные
+// операции по одному клиенту выполнялись строго последовательно.
+func lockClient(ctx context.Context, tx *sql.Tx, clientID domain.ClientID) error {
+	query := `select id from clients where id = $1 for update`
+
+	var id domain.ClientID
+	err := tx.QueryRowContext(ctx, query, clientID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewError(domain.ErrNotFound, "client %d not found", clientID)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to get debt: %w", err)
+		return fmt.Errorf("failed to lock client %d: %w", clientID, err)
 	}
 
-	return sumDebt, nil
+	return nil
 }
 
-func deleteDebt(ctx context.Context, tx *sql.Tx, photographerID domain.PhotographerID, clientID domain.ClientID) error {
-	query := `
-		delete from debts
-		where photographer_id = $1 and client_id = $2
-	`
+// lockActiveClient блокирует клиента, как lockClient, и отказывает, если клиент
+// в корзине: по удалённому клиенту нельзя начислять и проводить деньги.
+func lockActiveClient(ctx context.Context, tx *sql.Tx, clientID domain.ClientID) error {
+	query := `select deleted_at is not null from clients where id = $1 for update`
 
-	if _, err := tx.ExecContext(ctx, query, photographerID, clientID); err != nil {
-		return fmt.Errorf("failed to delete debt: %w", err)
+	var deleted bool
+	err := tx.QueryRowContext(ctx, query, clientID).Scan(&deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.NewError(domain.ErrNotFound, "client %d not found", clientID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock client %d: %w", clientID, err)
+	}
+	if deleted {
+		return domain.NewError(domain.ErrConflict, "client %d is deleted, restore it first", clientID)
 	}
 
 	return nil
 }
 
-func addPayment(ctx context.Context, tx *sql.Tx, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error {
+// getBalance считает баланс клиента в валюте его фотографа.
+func getBalance(ctx context.Context, q queryRower, clientID domain.ClientID) (domain.Balance, error) {
 	query := `
-		insert into payments (photographer_id, client_id, amount)
-		values ($1, $2, $3);
+		select ph.currency,
+		       coalesce((select sum(amount) from debts where client_id = c.id), 0),
+		       coalesce((select sum(amount) from payments where client_id = c.id), 0)
+		from clients c
+		join photographers ph on ph.id = c.photographer_id
+		where c.id = $1
 	`
 
-	if _, err := tx.ExecContext(ctx, query, photographerID, clientID, amount); err != nil {
-		return fmt.Errorf("failed to add payment: %w", err)
+	var charged, paid domain.Money
+	err := q.QueryRowContext(ctx, query, clientID).Scan(&charged.Currency, &charged.Amount, &paid.Amount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Balance{}, domain.NewError(domain.ErrNotFound, "client %d not found", clientID)
+	}
+	if err != nil {
+		return domain.Balance{}, fmt.Errorf("failed to get client balance: %w", err)
 	}
+	paid.Currency = charged.Currency
 
-	return nil
+	net, err := charged.Sub(paid)
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	return domain.NewBalance(net), nil
 }
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"photographer/internal/domain"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/lib/pq"
+)
+
+// testRepository подключается к базе из TEST_DATABASE_URL и накатывает на неё
+// миграции. Без переменной тесты с базой пропускаются.
+func testRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	m, err := migrate.New("file://../../migrations", dbURL)
+	if err != nil {
+		t.Fatalf("failed to prepare migrations: %v", err)
+	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return New(db)
+}
+
+// testClient заводит отдельного фотографа с клиентом, чтобы тесты не мешали
+// друг другу и данным в базе.
+func testClient(t *testing.T, r *Repository) (domain.PhotographerID, domain.ClientID) {
+	t.Helper()
+	ctx := context.Background()
+
+	photographerID, err := r.CreatePhotographer(ctx, domain.Photographer{
+		Name:     "Test",
+		Login:    fmt.Sprintf("test-%d", time.Now().UnixNano()),
+		TimeZone: domain.DefaultTimeZone,
+		Currency: domain.DefaultCurrency,
+	}, "hash")
+	if err != nil {
+		t.Fatalf("failed to create photographer: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = r.db.Exec(`delete from clients where photographer_id = $1`, photographerID)
+		_, _ = r.db.Exec(`delete from photographers where id = $1`, photographerID)
+	})
+
+	clientID, err := r.CreateClient(ctx, domain.Client{PhotographerID: photographerID, Name: "Client"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return photographerID, clientID
+}
+
+// Параллельные начисления и оплаты одному клиенту не теряют обновлений: каждая
+// операция записывается, а итоговый баланс равен их сумме.
+func TestConcurrentDebtsAndPayments(t *testing.T) {
+	r := testRepository(t)
+	photographerID, clientID := testClient(t, r)
+	ctx := context.Background()
+
+	const (
+		workers = 10
+		debt    = 1000
+		payment = 300
+	)
+
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, 2*workers)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _, err := r.AddDebt(ctx, domain.DebtEntry{
+				PhotographerID: photographerID,
+				ClientID:       clientID,
+				Amount:         domain.NewMoney(debt, domain.DefaultCurrency),
+			})
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := r.AddPayment(ctx, photographerID, domain.Payment{
+				ClientID:   clientID,
+				Amount:     domain.NewMoney(payment, domain.DefaultCurrency),
+				Method:     domain.PaymentMethodCash,
+				OccurredAt: time.Now(),
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent write failed: %v", err)
+		}
+	}
+
+	balance, err := r.GetClientBalance(ctx, clientID)
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+	if want := int64(workers * (debt - payment)); balance.Debt.Amount != want {
+		t.Errorf("debt = %d, want %d", balance.Debt.Amount, want)
+	}
+
+	var debts, payments int
+	err = r.db.QueryRow(`
+		select (select count(*) from debts where client_id = $1), (select count(*) from payments where client_id = $1)
+	`, clientID).Scan(&debts, &payments)
+	if err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	if debts != workers || payments != workers {
+		t.Errorf("rows = %d debts, %d payments, want %d of each", debts, payments, workers)
+	}
+}
+
+// inTx повторяет транзакцию при конфликте сериализации и взаимной блокировке и
+// не повторяет при остальных ошибках.
+func TestInTxRetry(t *testing.T) {
+	r := testRepository(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"serialization failure", &pq.Error{Code: "40001"}, 2},
+		{"deadlock", &pq.Error{Code: "40P01"}, 2},
+		{"unique violation", &pq.Error{Code: "23505"}, 1},
+		{"domain error", domain.NewError(domain.ErrConflict, "conflict"), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := r.inTx(ctx, func(tx *sql.Tx) error {
+				attempts++
+				if attempts == 1 {
+					return tt.err
+				}
+				return nil
+			})
+
+			if attempts != tt.attempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.attempts)
+			}
+			if tt.attempts == 1 && !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+			if tt.attempts > 1 && err != nil {
+				t.Errorf("err = %v, want nil after retry", err)
+			}
+		})
+	}
+
+	t.Run("gives up", func(t *testing.T) {
+		attempts := 0
+		err := r.inTx(ctx, func(tx *sql.Tx) error {
+			attempts++
+			return &pq.Error{Code: "40001"}
+		})
+		if attempts != maxTxAttempts {
+			t.Errorf("attempts = %d, want %d", attempts, maxTxAttempts)
+		}
+		if !isRetryable(err) {
+			t.Errorf("err = %v, want wrapped serialization failure", err)
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&pq.Error{Code: "40001"}, true},
+		{&pq.Error{Code: "40P01"}, true},
+		{fmt.Errorf("failed to add debt: %w", &pq.Error{Code: "40001"}), true},
+		{&pq.Error{Code: "23505"}, false},
+		{sql.ErrNoRows, false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// Изменение цены и отмена подтверждённой съёмки не трогают проведённое
+// начисление, а проводят корректировку и сторно.
+func TestSessionDebtReversals(t *testing.T) {
+	r := testRepository(t)
+	photographerID, clientID := testClient(t, r)
+	ctx := context.Background()
+
+	startsAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	session := domain.Session{
+		PhotographerID: photographerID,
+		ClientID:       clientID,
+		StartsAt:       startsAt,
+		EndsAt:         startsAt.Add(2 * time.Hour),
+		Status:         domain.SessionConfirmed,
+		Price:          domain.NewMoney(5000, domain.DefaultCurrency),
+	}
+	id, err := r.CreateSession(ctx, session)
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	session.ID = id
+	session.Price.Amount = 7000
+	if err = r.UpdateSession(ctx, session); err != nil {
+		t.Fatalf("failed to update session: %v", err)
+	}
+	if err = r.SetSessionStatus(ctx, id, domain.SessionCancelled); err != nil {
+		t.Fatalf("failed to cancel session: %v", err)
+	}
+
+	debts, err := r.GetClientDebts(ctx, clientID)
+	if err != nil {
+		t.Fatalf("failed to get debts: %v", err)
+	}
+
+	want := []struct {
+		kind   string
+		amount int64
+	}{
+		{domain.DebtKindCharge, 5000},
+		{domain.DebtKindAdjustment, 2000},
+		{domain.DebtKindVoid, -7000},
+	}
+	if len(debts) != len(want) {
+		t.Fatalf("got %d debts, want %d", len(debts), len(want))
+	}
+	for i, w := range want {
+		if debts[i].Kind != w.kind || debts[i].Amount.Amount != w.amount {
+			t.Errorf("debts[%d] = %s %d, want %s %d", i, debts[i].Kind, debts[i].Amount.Amount, w.kind, w.amount)
+		}
+		if i > 0 && (debts[i].ReversalOf == nil || *debts[i].ReversalOf != debts[0].ID) {
+			t.Errorf("debts[%d] does not reference the original charge %d", i, debts[0].ID)
+		}
+	}
+
+	balance, err := r.GetClientBalance(ctx, clientID)
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+	if balance.Debt.Amount != 0 {
+		t.Errorf("debt = %d, want 0", balance.Debt.Amount)
+	}
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+)
+
+// VoidPayment отменяет ошибочную оплату или возврат: исходное движение помечается
+// отменённым и остаётся в истории, а сторно той же датой возвращает баланс
+// клиента к состоянию без него.
+func (r *Repository) VoidPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID, reason string) (domain.Balance, error) {
+	var balance domain.Balance
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		payment, err := lockPayment(ctx, tx, photographerID, id)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case payment.Kind == domain.PaymentKindVoid:
+			return domain.NewError(domain.ErrConflict, "payment %d is a void entry and cannot be voided", id)
+		case payment.VoidedAt != nil:
+			return domain.NewError(domain.ErrConflict, "payment %d is already voided", id)
+		}
+
+		if payment.Kind == domain.PaymentKindPayment {
+			var refunded bool
+			err = tx.QueryRowContext(ctx, `
+				select exists(select 1 from payments where reversal_of = $1 and kind = $2 and voided_at is null)
+			`, id, domain.PaymentKindRefund).Scan(&refunded)
+			if err != nil {
+				return fmt.Errorf("failed to check payment refunds: %w", err)
+			}
+			if refunded {
+				return domain.NewError(domain.ErrConflict, "payment %d has refunds, void them first", id)
+			}
+		}
+
+		if _, err = tx.ExecContext(ctx, `update payments set voided_at = now() where id = $1`, id); err != nil {
+			return fmt.Errorf("failed to void payment: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			insert into payments (photographer_id, client_id, kind, amount, currency, method, reversal_of, reason, occurred_at)
+			values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, photographerID, payment.ClientID, domain.PaymentKindVoid, -payment.Amount.Amount, payment.Amount.Currency,
+			payment.Method, id, reason, payment.OccurredAt)
+		if err != nil {
+			return fmt.Errorf("failed to add void entry: %w", translateError(err))
+		}
+
+		balance, err = getBalance(ctx, tx, payment.ClientID)
+		return err
+	})
+	if err != nil {
+		return domain.Balance{}, err
+	}
+
+	return balance, nil
+}
+
+// RefundPayment возвращает клиенту часть оплаты или всю оплату. Сумма всех
+// действующих возвратов не может превысить саму оплату.
+func (r *Repository) RefundPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID,
+	amount domain.Money, reason string) (domain.PaymentID, domain.Balance, error) {
+	var (
+		refundID domain.PaymentID
+		balance  domain.Balance
+	)
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		payment, err := lockPayment(ctx, tx, photographerID, id)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case payment.Kind != domain.PaymentKindPayment:
+			return domain.NewError(domain.ErrConflict, "payment %d is a %s entry and cannot be refunded", id, payment.Kind)
+		case payment.VoidedAt != nil:
+			return domain.NewError(domain.ErrConflict, "payment %d is voided", id)
+		}
+
+		var refunded int64
+		err = tx.QueryRowContext(ctx, `
+			select coalesce(-sum(amount), 0) from payments where reversal_of = $1 and kind = $2 and voided_at is null
+		`, id, domain.PaymentKindRefund).Scan(&refunded)
+		if err != nil {
+			return fmt.Errorf("failed to get payment refunds: %w", err)
+		}
+
+		refundable := domain.NewMoney(payment.Amount.Amount-refunded, payment.Amount.Currency)
+		if amount.Amount > refundable.Amount {
+			var v domain.ValidationError
+			v.Add("amount", "must be at most %s, the amount not yet refunded", refundable)
+			return v.Err()
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			insert into payments (photographer_id, client_id, kind, amount, currency, method, reversal_of, reason)
+			values ($1, $2, $3, $4, $5, $6, $7, $8)
+			returning id
+		`, photographerID, payment.ClientID, domain.PaymentKindRefund, -amount.Amount, amount.Currency,
+			payment.Method, id, reason).Scan(&refundID)
+		if err != nil {
+			return fmt.Errorf("failed to add refund: %w", translateError(err))
+		}
+
+		balance, err = getBalance(ctx, tx, payment.ClientID)
+		return err
+	})
+	if err != nil {
+		return 0, domain.Balance{}, err
+	}
+
+	return refundID, balance, nil
+}
+
+// lockPayment блокирует клиента движения, а затем само движение: денежные
+// операции всегда берут блокировку клиента первой.
+func lockPayment(ctx context.Context, tx *sql.Tx, photographerID domain.PhotographerID, id domain.PaymentID) (domain.Payment, error) {
+	var clientID domain.ClientID
+	err := tx.QueryRowContext(ctx, `select client_id from payments where id = $1 and photographer_id = $2`,
+		id, photographerID).Scan(&clientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Payment{}, domain.NewError(domain.ErrNotFound, "payment %d not found", id)
+	}
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("failed to get payment %d: %w", id, err)
+	}
+
+	if err = lockClient(ctx, tx, clientID); err != nil {
+		return domain.Payment{}, err
+	}
+
+	var payment domain.Payment
+	err = tx.QueryRowContext(ctx, `
+		select id, client_id, kind, amount, currency, method, reference, note, reversal_of, reason, voided_at, occurred_at
+		from payments
+		where id = $1
+		for update
+	`, id).Scan(scanPayment(&payment)...)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("failed to lock payment %d: %w", id, err)
+	}
+
+	return payment, nil
+}
+
+func scanPayment(p *domain.Payment) []any {
+	return []any{&p.ID, &p.ClientID, &p.Kind, &p.Amount.Amount, &p.Amount.Currency, &p.Method, &p.Reference,
+		&p.Note, &p.ReversalOf, &p.Reason, &p.VoidedAt, &p.OccurredAt}
+}
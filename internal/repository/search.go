@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"photographer/internal/domain"
+)
+
+// SearchClients ищет неудалённых клиентов фотографа по имени, контактам и
+// заметкам с учётом опечаток. Совпадение в заметках весит меньше, чем в имени и
+// контактах. digits — цифры запроса для поиска по телефонам, записанным в любом
+// формате; пустая строка отключает этот поиск.
+func (r *Repository) SearchClients(ctx context.Context, photographerID domain.PhotographerID, query, digits string,
+	minScore float64, limit int) ([]domain.ClientMatch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		select c.id, c.photographer_id, c.name, c.contacts, c.birthday, c.source, c.notes, c.custom_fields,
+		       c.created_at, c.updated_at, c.deleted_at,
+		       coalesce(d.total, 0) - coalesce(p.total, 0) as balance, ph.currency, s.score
+		from clients c
+		join photographers ph on ph.id = c.photographer_id
+		left join (
+			select client_id, sum(amount) as total
+			from debts
+			where photographer_id = $1
+			group by client_id
+		) d on d.client_id = c.id
+		left join (
+			select client_id, sum(amount) as total
+			from payments
+			where photographer_id = $1
+			group by client_id
+		) p on p.client_id = c.id
+		cross join lateral (
+			select greatest(
+				word_similarity($2, c.name),
+				word_similarity($2, c.notes) * 0.8,
+				(
+					select max(case
+						when $3 <> '' and e->>'type' in ('phone', 'whatsapp')
+						     and regexp_replace(e->>'value', '\D', '', 'g') like '%' || $3 || '%' then 1
+						else word_similarity($2, e->>'value')
+					end)
+					from jsonb_array_elements(c.contacts) e
+				)
+			) as score
+		) s
+		where c.photographer_id = $1 and c.deleted_at is null and s.score >= $4
+		order by s.score desc, lower(c.name), c.id
+		limit $5
+	`, photographerID, query, digits, minScore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search clients: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []domain.ClientMatch
+	for rows.Next() {
+		var match domain.ClientMatch
+		c := &match.Client
+		if err = rows.Scan(append(scanClient(c), &c.Balance.Debt.Amount, &c.Balance.Debt.Currency, &match.Score)...); err != nil {
+			return nil, fmt.Errorf("failed to scan client: %w", err)
+		}
+		c.Balance = domain.NewBalance(c.Balance.Debt)
+		matches = append(matches, match)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan client: %w", err)
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+)
+
+const sessionColumns = `
+	s.id, s.photographer_id, s.client_id, c.name as client_name, s.starts_at, s.ends_at, s.location, s.type,
+	s.status, s.price, s.currency, s.note, s.created_at, s.updated_at
+`
+
+// CreateSession добавляет съёмку. Подтверждённая сразу съёмка начисляется
+// клиенту в той же транзакции.
+func (r *Repository) CreateSession(ctx context.Context, session domain.Session) (domain.SessionID, error) {
+	var id domain.SessionID
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		id, err = insertSession(ctx, tx, session)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// CreateSessions добавляет съёмки одной транзакцией: если хотя бы одна
+// пересекается с другой, не добавляется ни одна. Ошибка указывает номер съёмки
+// с нуля, как в исходном списке.
+func (r *Repository) CreateSessions(ctx context.Context, sessions []domain.Session) ([]domain.SessionID, error) {
+	ids := make([]domain.SessionID, 0, len(sessions))
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		for i, session := range sessions {
+			id, err := insertSession(ctx, tx, session)
+			var domainErr *domain.Error
+			if errors.As(err, &domainErr) {
+				return domain.NewError(domainErr.Kind, "session %d: %s", i, domainErr.Message)
+			}
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func insertSession(ctx context.Context, tx *sql.Tx, session domain.Session) (domain.SessionID, error) {
+	query := `
+		insert into sessions (photographer_id, client_id, starts_at, ends_at, location, type, status, price, currency, note)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		returning id
+	`
+
+	if err := lockActiveClient(ctx, tx, session.ClientID); err != nil {
+		return 0, err
+	}
+
+	var id domain.SessionID
+	err := tx.QueryRowContext(ctx, query, session.PhotographerID, session.ClientID, session.StartsAt, session.EndsAt,
+		session.Location, session.Type, session.Status, session.Price.Amount, session.Price.Currency,
+		session.Note).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create session: %w", translateError(err))
+	}
+
+	if session.Status == domain.SessionConfirmed {
+		if err = syncSessionDebt(ctx, tx, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+// UpdateSession меняет время, место, тип, цену и примечание съёмки. У
+// подтверждённой съёмки изменение цены проводится корректировкой её начисления.
+func (r *Repository) UpdateSession(ctx context.Context, session domain.Session) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		locked, err := lockSession(ctx, tx, session.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status == domain.SessionCompleted || locked.Status == domain.SessionCancelled {
+			return domain.NewError(domain.ErrConflict, "session %d is %s and cannot be edited", session.ID, locked.Status)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			update sessions
+			set starts_at = $2, ends_at = $3, location = $4, type = $5, price = $6, currency = $7, note = $8,
+			    updated_at = now()
+			where id = $1
+		`, session.ID, session.StartsAt, session.EndsAt, session.Location, session.Type, session.Price.Amount,
+			session.Price.Currency, session.Note)
+		if err != nil {
+			return fmt.Errorf("failed to update session: %w", translateError(err))
+		}
+
+		if locked.Status == domain.SessionConfirmed {
+			return syncSessionDebt(ctx, tx, session.ID)
+		}
+		return nil
+	})
+}
+
+// DeleteSession удаляет ошибочно заведённую съёмку. Подтверждённую или
+// проведённую съёмку удалить нельзя: она уже начислена клиенту.
+func (r *Repository) DeleteSession(ctx context.Context, id domain.SessionID) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		session, err := lockSession(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if session.Status == domain.SessionConfirmed || session.Status == domain.SessionCompleted {
+			return domain.NewError(domain.ErrConflict, "session %d is %s, cancel it instead", id, session.Status)
+		}
+
+		if _, err = tx.ExecContext(ctx, `delete from sessions where id = $1`, id); err != nil {
+			return fmt.Errorf("failed to delete session: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) GetSession(ctx context.Context, id domain.SessionID) (domain.Session, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from sessions s
+		join clients c on c.id = s.client_id
+		where s.id = $1
+	`, sessionColumns)
+
+	var session domain.Session
+	err := r.db.QueryRowContext(ctx, query, id).Scan(scanSession(&session)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Session{}, domain.NewError(domain.ErrNotFound, "session %d not found", id)
+	}
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+var sessionsList = listSpec{
+	sorts: map[string]sortColumn{
+		"amount": {column: "price", cast: "bigint"},
+		"date":   {column: "starts_at", cast: "timestamptz"},
+	},
+	defaultSort:   "date",
+	dateColumn:    "starts_at",
+	amountColumn:  "price",
+	clientColumn:  "client_id",
+	deletedColumn: "deleted_at",
+}
+
+// GetSessions возвращает съёмки фотографа, фильтр по дате — по началу съёмки.
+// Пустой status означает съёмки в любом статусе.
+func (r *Repository) GetSessions(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Session], error) {
+	base := fmt.Sprintf(`
+		select %s, c.deleted_at
+		from sessions s
+		join clients c on c.id = s.client_id
+		where s.photographer_id = $1 and ($2 = '' or s.status = $2)
+	`, sessionColumns)
+
+	query, args, err := sessionsList.build(base,
+		"t.id, t.photographer_id, t.client_id, t.client_name, t.starts_at, t.ends_at, t.location, t.type, "+
+			"t.status, t.price, t.currency, t.note, t.created_at, t.updated_at",
+		[]any{photographerID, status}, &params)
+	if err != nil {
+		return domain.Page[domain.Session]{}, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.Page[domain.Session]{}, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	page, err := scanPage(rows, params, scanSession, func(s domain.Session) int64 { return int64(s.ID) })
+	if err != nil {
+		return domain.Page[domain.Session]{}, fmt.Errorf("failed to scan session: %w", err)
+	}
+
+	return page, nil
+}
+
+// SetSessionStatus переводит съёмку в новый статус. Подтверждение начисляет цену
+// съёмки клиенту, отмена подтверждённой съёмки сторнирует начисление.
+func (r *Repository) SetSessionStatus(ctx context.Context, id domain.SessionID, status string) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		session, err := lockSession(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !domain.CanTransitSession(session.Status, status) {
+			return domain.NewError(domain.ErrConflict, "session %d is %s and cannot become %s", id, session.Status, status)
+		}
+
+		_, err = tx.ExecContext(ctx, `update sessions set status = $2, updated_at = now() where id = $1`, id, status)
+		if err != nil {
+			return fmt.Errorf("failed to set session status: %w", translateError(err))
+		}
+
+		if status == domain.SessionConfirmed || session.Status == domain.SessionConfirmed {
+			return syncSessionDebt(ctx, tx, id)
+		}
+
+		return nil
+	})
+}
+
+// syncSessionDebt приводит начисления съёмки в соответствие с её ценой и
+// статусом: подтверждённая или проведённая съёмка должна клиенту свою цену,
+// остальные — ничего. Проведённое начисление не меняется: разница проводится
+// корректировкой, а снятие начисления целиком — сторно исходного начисления.
+func syncSessionDebt(ctx context.Context, tx *sql.Tx, id domain.SessionID) error {
+	var (
+		session     domain.Session
+		description string
+		chargeID    *domain.DebtID
+		net         int64
+		voided      bool
+	)
+	err := tx.QueryRowContext(ctx, `
+		select s.photographer_id, s.client_id, s.status, s.price, s.currency,
+		       'Съёмка ' || to_char(s.starts_at at time zone ph.time_zone, 'DD.MM.YYYY') ||
+		           case when s.type <> '' then ', ' || s.type else '' end,
+		       (select min(id) from debts where session_id = s.id and reversal_of is null),
+		       (select coalesce(sum(amount), 0) from debts where session_id = s.id),
+		       exists (select 1 from debts where session_id = s.id and kind = 'void')
+		from sessions s
+		join photographers ph on ph.id = s.photographer_id
+		where s.id = $1
+	`, id).Scan(&session.PhotographerID, &session.ClientID, &session.Status, &session.Price.Amount,
+		&session.Price.Currency, &description, &chargeID, &net, &voided)
+	if err != nil {
+		return fmt.Errorf("failed to get session debt: %w", err)
+	}
+
+	var target int64
+	if session.Status == domain.SessionConfirmed || session.Status == domain.SessionCompleted {
+		target = session.Price.Amount
+	}
+
+	query := `
+		insert into debts (photographer_id, client_id, kind, amount, currency, description, session_id, reversal_of)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	args := []any{session.PhotographerID, session.ClientID}
+	switch {
+	case chargeID == nil && target > 0:
+		args = append(args, domain.DebtKindCharge, target, session.Price.Currency, description, id, nil)
+	case chargeID == nil || target == net:
+		return nil
+	case target == 0 && !voided:
+		args = append(args, domain.DebtKindVoid, -net, session.Price.Currency, "Сторно: "+description, id, *chargeID)
+	default:
+		args = append(args, domain.DebtKindAdjustment, target-net, session.Price.Currency,
+			"Корректировка цены: "+description, id, *chargeID)
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to sync session debt: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// lockSession блокирует клиента съёмки, а затем саму съёмку до конца транзакции.
+func lockSession(ctx context.Context, tx *sql.Tx, id domain.SessionID) (domain.Session, error) {
+	var clientID domain.ClientID
+	err := tx.QueryRowContext(ctx, `select client_id from sessions where id = $1`, id).Scan(&clientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Session{}, domain.NewError(domain.ErrNotFound, "session %d not found", id)
+	}
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("failed to get session %d: %w", id, err)
+	}
+
+	if err = lockClient(ctx, tx, clientID); err != nil {
+		return domain.Session{}, err
+	}
+
+	query := fmt.Sprintf(`
+		select %s
+		from sessions s
+		join clients c on c.id = s.client_id
+		where s.id = $1
+		for update of s
+	`, sessionColumns)
+
+	var session domain.Session
+	if err = tx.QueryRowContext(ctx, query, id).Scan(scanSession(&session)...); err != nil {
+		return domain.Session{}, fmt.Errorf("failed to lock session %d: %w", id, err)
+	}
+
+	return session, nil
+}
+
+func scanSession(s *domain.Session) []any {
+	return []any{&s.ID, &s.PhotographerID, &s.ClientID, &s.ClientName, &s.StartsAt, &s.EndsAt, &s.Location, &s.Type,
+		&s.Status, &s.Price.Amount, &s.Price.Currency, &s.Note, &s.CreatedAt, &s.UpdatedAt}
+}
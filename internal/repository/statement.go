@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+// statementMovements — все операции клиента: движения по начислениям со знаком
+// плюс, движения по оплатам со знаком минус, так что возвраты и сторно оплат
+// увеличивают баланс. source говорит, из какой таблицы строка: виды движений
+// у начислений и оплат пересекаются. $1 — ID клиента.
+const statementMovements = `
+	select 'debt' as source, kind, id, reversal_of, amount, description, occurred_at
+	from debts
+	where client_id = $1
+	union all
+	select 'payment', kind, id, reversal_of, -amount, case when kind = 'payment' then note else reason end, occurred_at
+	from payments
+	where client_id = $1
+`
+
+// GetStatement возвращает операции клиента за период [from, to) с балансом после
+// каждой из них, а также баланс на начало и конец периода. Без from период
+// начинается с первой операции.
+func (r *Repository) GetStatement(ctx context.Context, clientID domain.ClientID, from *time.Time, to time.Time) (domain.Statement, error) {
+	balancesQuery := fmt.Sprintf(`
+		select coalesce(sum(m.amount) filter (where m.occurred_at < $2::timestamptz), 0),
+		       coalesce(sum(m.amount) filter (where m.occurred_at < $3::timestamptz), 0),
+		       coalesce(sum(m.amount) filter (where m.source = 'debt' and m.occurred_at >= coalesce($2, '-infinity') and m.occurred_at < $3), 0),
+		       coalesce(-sum(m.amount) filter (where m.source = 'payment' and m.occurred_at >= coalesce($2, '-infinity') and m.occurred_at < $3), 0),
+		       ph.currency
+		from clients c
+		join photographers ph on ph.id = c.photographer_id
+		left join (%s) m on true
+		where c.id = $1
+		group by ph.currency
+	`, statementMovements)
+
+	var (
+		statement        domain.Statement
+		opening, closing int64
+		currency         string
+	)
+	err := r.db.QueryRowContext(ctx, balancesQuery, clientID, from, to).Scan(&opening, &closing,
+		&statement.Charged.Amount, &statement.Paid.Amount, &currency)
+	if err != nil {
+		return domain.Statement{}, fmt.Errorf("failed to get statement balances: %w", translateError(err))
+	}
+	statement.Opening = domain.NewBalance(domain.NewMoney(opening, currency))
+	statement.Closing = domain.NewBalance(domain.NewMoney(closing, currency))
+	statement.Charged.Currency, statement.Paid.Currency = currency, currency
+
+	entriesQuery := fmt.Sprintf(`
+		select source, kind, id, reversal_of, case when source = 'debt' then amount else -amount end, description,
+		       occurred_at, running
+		from (
+			select m.*, sum(m.amount) over (order by m.occurred_at, m.source, m.id) as running
+			from (%s) m
+			where m.occurred_at < $3::timestamptz
+		) t
+		where $2::timestamptz is null or occurred_at >= $2
+		order by occurred_at, source, id
+	`, statementMovements)
+
+	rows, err := r.db.QueryContext(ctx, entriesQuery, clientID, from, to)
+	if err != nil {
+		return domain.Statement{}, fmt.Errorf("failed to get statement entries: %w", err)
+	}
+	defer rows.Close()
+
+	statement.Entries = []domain.StatementEntry{}
+	for rows.Next() {
+		var (
+			entry   domain.StatementEntry
+			running int64
+		)
+		if err = rows.Scan(&entry.Source, &entry.Kind, &entry.ID, &entry.ReversalOf, &entry.Amount.Amount,
+			&entry.Description, &entry.OccurredAt, &running); err != nil {
+			return domain.Statement{}, fmt.Errorf("failed to scan statement entry: %w", err)
+		}
+		entry.Amount.Currency = currency
+		entry.Balance = domain.NewBalance(domain.NewMoney(running, currency))
+		statement.Entries = append(statement.Entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.Statement{}, fmt.Errorf("failed to scan statement entry: %w", err)
+	}
+
+	return statement, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+// anonymizedClientName заменяет имя клиента после обезличивания.
+const anonymizedClientName = "Удалённый клиент"
+
+var trashList = listSpec{
+	sorts: map[string]sortColumn{
+		"name":    {column: "name", cast: "text"},
+		"amount":  {column: "balance", cast: "numeric"},
+		"date":    {column: "created_at", cast: "timestamptz"},
+		"deleted": {column: "deleted_at", cast: "timestamptz"},
+	},
+	defaultSort:  "-deleted",
+	dateColumn:   "deleted_at",
+	amountColumn: "balance",
+}
+
+// GetDeletedClients возвращает клиентов в корзине: удалённых, но ещё не
+// обезличенных.
+func (r *Repository) GetDeletedClients(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error) {
+	return r.getClients(ctx, trashList, clientsBase+" and c.deleted_at is not null and c.anonymized_at is null",
+		photographerID, params)
+}
+
+// RestoreClient возвращает клиента из корзины. Клиента, поглощённого при
+// объединении, восстановить нельзя: его записи уже у другого клиента.
+func (r *Repository) RestoreClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		var targetID domain.ClientID
+		err := tx.QueryRowContext(ctx, `select target_id from client_merges where source_id = $1`, id).Scan(&targetID)
+		switch {
+		case err == nil:
+			return domain.NewError(domain.ErrConflict, "client %d was merged into client %d and cannot be restored", id, targetID)
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("failed to check client merges: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx, `
+			update clients
+			set deleted_at = null, updated_at = now()
+			where id = $1 and photographer_id = $2 and deleted_at is not null and anonymized_at is null
+		`, id, photographerID)
+		if err != nil {
+			return fmt.Errorf("failed to restore client: %w", translateError(err))
+		}
+
+		return checkAffected(res, "client %d not found in trash", id)
+	})
+}
+
+// PurgeDeletedClients окончательно обрабатывает клиентов, пролежавших в корзине
+// дольше retention. При anonymize карточка обезличивается, а начисления и оплаты
+// остаются в отчётах; иначе удаляются только клиенты без выставленных счетов,
+// начислений и оплат, а остальные обезличиваются, чтобы не пропали деньги из
+// отчётов. Клиенты, поглощённые при объединении, всегда только обезличиваются,
+// чтобы не пропала история объединений.
+func (r *Repository) PurgeDeletedClients(ctx context.Context, retention time.Duration, anonymize bool) (int64, error) {
+	var deleted int64
+	if !anonymize {
+		res, err := r.db.ExecContext(ctx, `
+			delete from clients
+			where deleted_at < now() - make_interval(secs => $1)
+			  and not exists (select 1 from client_merges m where m.source_id = clients.id)
+			  and not exists (select 1 from invoices i where i.client_id = clients.id and i.status <> 'draft')
+			  and not exists (select 1 from debts d where d.client_id = clients.id)
+			  and not exists (select 1 from payments p where p.client_id = clients.id)
+		`, retention.Seconds())
+		if err != nil {
+			return 0, fmt.Errorf("failed to purge deleted clients: %w", err)
+		}
+		if deleted, err = res.RowsAffected(); err != nil {
+			return 0, fmt.Errorf("failed to purge deleted clients: %w", err)
+		}
+	}
+
+	var anonymized int64
+	err := r.db.QueryRowContext(ctx, `
+		with purged as (
+			update clients
+			set name = $2, contacts = '[]', birthday = null, source = '', notes = '', custom_fields = '{}',
+			    anonymized_at = now(), updated_at = now()
+			where deleted_at < now() - make_interval(secs => $1) and anonymized_at is null
+			returning id
+		), merges as (
+			update client_merges set source_name = $2 where source_id in (select id from purged)
+		)
+		select count(*) from purged
+	`, retention.Seconds(), anonymizedClientName).Scan(&anonymized)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to anonymize deleted clients: %w", err)
+	}
+
+	return deleted + anonymized, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	maxTxAttempts = 5
+	txRetryDelay  = 10 * time.Millisecond
+)
+
+// inTx выполняет fn в serializable-транзакции, привязанной к контексту запроса.
+// При конфликте сериализации или взаимной блокировке транзакция повторяется
+// с нарастающей задержкой.
+func (r *Repository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		if err = r.runTx(ctx, fn); err == nil || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+
+	return fmt.Errorf("transaction aborted after %d attempts: %w", maxTxAttempts, err)
+}
+
+func (r *Repository) runTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	switch pqErr.Code {
+	case "40001", // serialization_failure
+		"40P01": // deadlock_detected
+		return true
+	}
+
+	return false
+}
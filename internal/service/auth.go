@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"photographer/internal/domain"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const tokenBytes = 32
+
+var errInvalidCredentials = domain.NewError(domain.ErrUnauthorized, "invalid login or password")
+
+// Login проверяет пароль фотографа и выдаёт новый токен доступа.
+func (s *Service) Login(ctx context.Context, login, password string) (domain.AuthToken, error) {
+	id, passwordHash, err := s.repo.GetPhotographerCredentials(ctx, login)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.AuthToken{}, errInvalidCredentials
+	}
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)); err != nil {
+		return domain.AuthToken{}, errInvalidCredentials
+	}
+
+	loc, err := s.location(ctx, id)
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	raw, err := newToken()
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	token := domain.AuthToken{
+		Token:          raw,
+		PhotographerID: id,
+		ExpiresAt:      time.Now().Add(s.opts.AuthTokenTTL).In(loc),
+	}
+
+	if err = s.repo.CreateAuthToken(ctx, hashToken(token.Token), id, token.ExpiresAt); err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	return token, nil
+}
+
+// Authenticate возвращает сведения о токене: кому он выдан и деактивирован ли
+// фотограф. Сам токен в ответе не возвращается.
+func (s *Service) Authenticate(ctx context.Context, token string) (domain.AuthToken, error) {
+	stored, err := s.repo.GetAuthToken(ctx, hashToken(token))
+	if err != nil {
+		return domain.AuthToken{}, err
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return domain.AuthToken{}, domain.NewError(domain.ErrUnauthorized, "token expired")
+	}
+
+	return stored, nil
+}
+
+func (s *Service) Logout(ctx context.Context, token string) error {
+	return s.repo.DeleteAuthToken(ctx, hashToken(token))
+}
+
+// PurgeAuthTokens периодически удаляет истёкшие токены, пока не отменён ctx.
+func (s *Service) PurgeAuthTokens(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteExpiredAuthTokens(ctx)
+			if err != nil {
+				log.Printf("purge auth tokens error: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("purged %d expired auth tokens", deleted)
+			}
+		}
+	}
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return string(hash), nil
+}
+
+// newToken возвращает случайный токен, пригодный для передачи в URL.
+func newToken() (string, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
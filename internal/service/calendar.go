@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+const (
+	// calendarHistory — сколько прошедших съёмок остаётся в ленте календаря.
+	calendarHistory = 90 * 24 * time.Hour
+
+	maxImportSessions = 500
+)
+
+// RotateCalendarToken выдаёт фотографу новый токен ленты календаря, прежняя
+// ссылка на ленту перестаёт работать.
+func (s *Service) RotateCalendarToken(ctx context.Context, photographerID domain.PhotographerID) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.repo.SetCalendarToken(ctx, photographerID, hashToken(token)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *Service) RevokeCalendarToken(ctx context.Context, photographerID domain.PhotographerID) error {
+	return s.repo.SetCalendarToken(ctx, photographerID, "")
+}
+
+// GetCalendarFeed возвращает ленту по токену календаря: предстоящие съёмки и
+// съёмки за последние 90 дней, включая отменённые, чтобы календарь их убрал.
+func (s *Service) GetCalendarFeed(ctx context.Context, token string) (domain.CalendarFeed, error) {
+	photographerID, err := s.repo.GetCalendarPhotographer(ctx, hashToken(token))
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+
+	photographer, err := s.GetPhotographer(ctx, photographerID)
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+
+	loc, err := loadLocation(photographer.TimeZone)
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+
+	events, err := s.repo.GetCalendarEvents(ctx, photographerID, time.Now().Add(-calendarHistory))
+	if err != nil {
+		return domain.CalendarFeed{}, err
+	}
+
+	for i, event := range events {
+		events[i].Session = sessionInZone(event.Session, loc)
+	}
+
+	return domain.CalendarFeed{Photographer: photographer, Events: events}, nil
+}
+
+// ImportSessions добавляет клиенту предварительные съёмки из календаря. Съёмки
+// проверяются и добавляются вместе: при любой ошибке не добавляется ни одна.
+func (s *Service) ImportSessions(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID,
+	sessions []domain.Session) ([]domain.SessionID, error) {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return nil, err
+	}
+
+	var v domain.ValidationError
+	validateID(&v, "client_id", clientID)
+	switch {
+	case len(sessions) == 0:
+		v.Add("events", "calendar has no events to import")
+	case len(sessions) > maxImportSessions:
+		v.Add("events", "must be at most %d events", maxImportSessions)
+	}
+	if err := s.validateClientForMoney(ctx, &v, photographerID, clientID); err != nil {
+		return nil, err
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].PhotographerID = photographerID
+		sessions[i].ClientID = clientID
+		sessions[i].Status = domain.SessionTentative
+
+		var sv domain.ValidationError
+		if err := s.validateSession(ctx, &sv, &sessions[i]); err != nil {
+			return nil, err
+		}
+		for _, f := range sv.Fields {
+			v.Add(fmt.Sprintf("events[%d].%s", i, f.Field), "%s", f.Message)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateSessions(ctx, sessions)
+}
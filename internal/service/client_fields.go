@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/mail"
+	"photographer/internal/domain"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxClientContacts     = 20
+	maxClientFields       = 50
+	maxClientFieldOptions = 100
+	maxNotesLength        = 10000
+)
+
+var (
+	clientFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+	phonePattern          = regexp.MustCompile(`^\+?[0-9 ()\-]{5,32}$`)
+)
+
+func (s *Service) CreateClientField(ctx context.Context, field domain.ClientField) (domain.ClientFieldID, error) {
+	var v domain.ValidationError
+	validateID(&v, "photographer_id", field.PhotographerID)
+	field.Key = strings.TrimSpace(field.Key)
+	if !clientFieldKeyPattern.MatchString(field.Key) {
+		v.Add("key", "must start with a lowercase latin letter and contain only a-z, 0-9 and _, at most 64 characters")
+	}
+	switch field.Type {
+	case domain.ClientFieldText, domain.ClientFieldNumber, domain.ClientFieldDate, domain.ClientFieldSelect:
+	default:
+		v.Add("type", "must be one of text, number, date, select")
+	}
+	validateClientField(&v, &field)
+
+	if field.PhotographerID > 0 {
+		fields, err := s.repo.GetClientFields(ctx, field.PhotographerID)
+		if err != nil {
+			return 0, err
+		}
+		if len(fields) >= maxClientFields {
+			v.Add("key", "photographer already has %d client fields", maxClientFields)
+		}
+	}
+	if err := v.Err(); err != nil {
+		return 0, err
+	}
+
+	return s.repo.CreateClientField(ctx, field)
+}
+
+// UpdateClientField меняет подпись и варианты поля. Значения клиентов, которых
+// больше нет среди вариантов, остаются до следующего изменения этого поля.
+func (s *Service) UpdateClientField(ctx context.Context, field domain.ClientField) error {
+	stored, err := s.ownedClientField(ctx, field.PhotographerID, field.ID)
+	if err != nil {
+		return err
+	}
+	field.Type = stored.Type
+
+	var v domain.ValidationError
+	validateClientField(&v, &field)
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateClientField(ctx, field)
+}
+
+func (s *Service) DeleteClientField(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientFieldID) error {
+	if _, err := s.ownedClientField(ctx, photographerID, id); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteClientField(ctx, id)
+}
+
+func (s *Service) GetClientFields(ctx context.Context, photographerID domain.PhotographerID) ([]domain.ClientField, error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := s.repo.GetClientFields(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range fields {
+		fields[i].CreatedAt = fields[i].CreatedAt.In(loc)
+	}
+
+	return fields, nil
+}
+
+// validateClientField проверяет подпись и варианты поля заданного типа.
+func validateClientField(v *domain.ValidationError, field *domain.ClientField) {
+	validateName(v, "label", field.Label)
+	field.Label = strings.TrimSpace(field.Label)
+
+	if field.Type != domain.ClientFieldSelect {
+		if len(field.Options) > 0 {
+			v.Add("options", "are allowed only for select fields")
+		}
+		field.Options = []string{}
+		return
+	}
+
+	switch {
+	case len(field.Options) == 0:
+		v.Add("options", "must not be empty for select fields")
+	case len(field.Options) > maxClientFieldOptions:
+		v.Add("options", "must contain at most %d items", maxClientFieldOptions)
+	}
+	options := make([]string, 0, len(field.Options))
+	for i, option := range field.Options {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "":
+			v.Add(fmt.Sprintf("options[%d]", i), "must not be empty")
+		case utf8.RuneCountInString(option) > maxNameLength:
+			v.Add(fmt.Sprintf("options[%d]", i), "must be at most %d characters", maxNameLength)
+		case slices.Contains(options, option):
+			v.Add(fmt.Sprintf("options[%d]", i), "duplicates another option")
+		}
+		options = append(options, option)
+	}
+	field.Options = options
+}
+
+// validateProfile проверяет карточку клиента и приводит её к хранимому виду.
+// Свои поля проверяются только из changed: значения, сохранённые раньше, не
+// мешают менять остальные поля, даже если поле с тех пор изменилось.
+func (s *Service) validateProfile(ctx context.Context, v *domain.ValidationError, client *domain.Client, changed map[string]any) error {
+	if len(client.Contacts) > maxClientContacts {
+		v.Add("contacts", "must contain at most %d items", maxClientContacts)
+	}
+	for i := range client.Contacts {
+		validateContact(v, fmt.Sprintf("contacts[%d]", i), &client.Contacts[i])
+	}
+
+	if client.Birthday != nil && client.Birthday.After(time.Now()) {
+		v.Add("birthday", "must not be in the future")
+	}
+
+	client.Source = strings.TrimSpace(client.Source)
+	if utf8.RuneCountInString(client.Source) > maxNameLength {
+		v.Add("source", "must be at most %d characters", maxNameLength)
+	}
+	client.Notes = strings.TrimSpace(client.Notes)
+	if utf8.RuneCountInString(client.Notes) > maxNotesLength {
+		v.Add("notes", "must be at most %d characters", maxNotesLength)
+	}
+
+	if len(changed) == 0 || client.PhotographerID <= 0 {
+		return nil
+	}
+
+	fields, err := s.repo.GetClientFields(ctx, client.PhotographerID)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]domain.ClientField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	for key, value := range changed {
+		name := "custom_fields." + key
+		field, ok := byKey[key]
+		switch {
+		case !ok:
+			v.Add(name, "unknown client field")
+		case value == nil:
+			delete(client.CustomFields, key)
+		default:
+			if value, ok = customFieldValue(v, name, field, value); ok {
+				client.CustomFields[key] = value
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateContact(v *domain.ValidationError, field string, contact *domain.Contact) {
+	contact.Type = strings.TrimSpace(contact.Type)
+	contact.Value = strings.TrimSpace(contact.Value)
+	contact.Label = strings.TrimSpace(contact.Label)
+
+	switch contact.Type {
+	case domain.ContactPhone, domain.ContactEmail, domain.ContactInstagram, domain.ContactTelegram,
+		domain.ContactWhatsApp, domain.ContactVK, domain.ContactOther:
+	default:
+		v.Add(field+".type", "must be one of phone, email, instagram, telegram, whatsapp, vk, other")
+	}
+
+	switch {
+	case contact.Value == "":
+		v.Add(field+".value", "must not be empty")
+	case utf8.RuneCountInString(contact.Value) > maxNameLength:
+		v.Add(field+".value", "must be at most %d characters", maxNameLength)
+	case contact.Type == domain.ContactPhone && !phonePattern.MatchString(contact.Value):
+		v.Add(field+".value", "must be a phone number, e.g. +7 900 123-45-67")
+	case contact.Type == domain.ContactEmail:
+		if address, err := mail.ParseAddress(contact.Value); err != nil || address.Address != contact.Value {
+			v.Add(field+".value", "must be an email address")
+		}
+	}
+
+	if utf8.RuneCountInString(contact.Label) > maxNameLength {
+		v.Add(field+".label", "must be at most %d characters", maxNameLength)
+	}
+}
+
+// customFieldValue проверяет значение своего поля клиента по его типу и
+// возвращает значение в хранимом виде.
+func customFieldValue(v *domain.ValidationError, name string, field domain.ClientField, value any) (any, bool) {
+	if field.Type == domain.ClientFieldNumber {
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			v.Add(name, "must be a number")
+			return nil, false
+		}
+		return number, true
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		v.Add(name, "must be a string")
+		return nil, false
+	}
+	text = strings.TrimSpace(text)
+
+	switch field.Type {
+	case domain.ClientFieldDate:
+		if _, err := time.Parse(time.DateOnly, text); err != nil {
+			v.Add(name, "must be a date YYYY-MM-DD")
+			return nil, false
+		}
+	case domain.ClientFieldSelect:
+		if !slices.Contains(field.Options, text) {
+			v.Add(name, "must be one of the field options")
+			return nil, false
+		}
+	default:
+		if utf8.RuneCountInString(text) > maxDescriptionLength {
+			v.Add(name, "must be at most %d characters", maxDescriptionLength)
+			return nil, false
+		}
+	}
+
+	return text, true
+}
+
+// ownedClientField возвращает поле фотографа, чужое поле неотличимо от несуществующего.
+func (s *Service) ownedClientField(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientFieldID) (domain.ClientField, error) {
+	field, err := s.repo.GetClientField(ctx, id)
+	if err != nil {
+		return domain.ClientField{}, err
+	}
+
+	if field.PhotographerID != photographerID {
+		return domain.ClientField{}, domain.NewError(domain.ErrNotFound, "client field %d not found", id)
+	}
+
+	return field, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"log"
+	"photographer/internal/domain"
+	"time"
+)
+
+// ReserveIdempotencyKey занимает ключ идемпотентности. Если ключ уже использовался
+// в пределах IdempotencyTTL, возвращается сохранённая запись и false.
+func (s *Service) ReserveIdempotencyKey(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	return s.repo.ReserveIdempotencyKey(ctx, record, s.opts.IdempotencyTTL)
+}
+
+func (s *Service) SaveIdempotencyResult(ctx context.Context, record domain.IdempotencyRecord) error {
+	return s.repo.SaveIdempotencyResult(ctx, record)
+}
+
+func (s *Service) ReleaseIdempotencyKey(ctx context.Context, key, scope string) error {
+	return s.repo.ReleaseIdempotencyKey(ctx, key, scope)
+}
+
+// PurgeIdempotencyKeys периодически удаляет истёкшие ключи, пока не отменён ctx.
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteExpiredIdempotencyKeys(ctx, s.opts.IdempotencyTTL)
+			if err != nil {
+				log.Printf("purge idempotency keys error: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("purged %d expired idempotency keys", deleted)
+			}
+		}
+	}
+}
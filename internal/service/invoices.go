@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"photographer/internal/domain"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxInvoiceItems    = 100
+	maxInvoiceQuantity = 10000
+)
+
+// CreateInvoice создаёт черновик счёта клиенту. Номер и начисление счёт получает
+// только при выставлении.
+func (s *Service) CreateInvoice(ctx context.Context, invoice domain.Invoice) (domain.InvoiceID, error) {
+	if err := s.checkActive(ctx, invoice.PhotographerID); err != nil {
+		return 0, err
+	}
+
+	var v domain.ValidationError
+	validateID(&v, "client_id", invoice.ClientID)
+	if err := s.validateInvoice(ctx, &v, &invoice); err != nil {
+		return 0, err
+	}
+	if err := s.validateClientForMoney(ctx, &v, invoice.PhotographerID, invoice.ClientID); err != nil {
+		return 0, err
+	}
+	if err := v.Err(); err != nil {
+		return 0, err
+	}
+
+	return s.repo.CreateInvoice(ctx, invoice)
+}
+
+// UpdateInvoice заменяет содержимое черновика. Выставленный счёт не меняется,
+// его можно только аннулировать.
+func (s *Service) UpdateInvoice(ctx context.Context, invoice domain.Invoice) error {
+	if err := s.checkActive(ctx, invoice.PhotographerID); err != nil {
+		return err
+	}
+
+	existing, err := s.ownedInvoice(ctx, invoice.PhotographerID, invoice.ID)
+	if err != nil {
+		return err
+	}
+	invoice.ClientID = existing.ClientID
+
+	var v domain.ValidationError
+	if err = s.validateInvoice(ctx, &v, &invoice); err != nil {
+		return err
+	}
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateInvoice(ctx, invoice)
+}
+
+func (s *Service) GetInvoice(ctx context.Context, photographerID domain.PhotographerID, id domain.InvoiceID) (domain.Invoice, error) {
+	invoice, err := s.ownedInvoice(ctx, photographerID, id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+
+	return invoiceInZone(invoice, loc), nil
+}
+
+// GetInvoices возвращает счета фотографа, пустой status — счета в любом статусе.
+func (s *Service) GetInvoices(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Invoice], error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Page[domain.Invoice]{}, err
+	}
+
+	var v domain.ValidationError
+	if status != "" {
+		validateInvoiceStatus(&v, status)
+	}
+	if err = v.Err(); err != nil {
+		return domain.Page[domain.Invoice]{}, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return domain.Page[domain.Invoice]{}, err
+	}
+
+	page, err := s.repo.GetInvoices(ctx, photographerID, status, params)
+	if err != nil {
+		return domain.Page[domain.Invoice]{}, err
+	}
+
+	for i, invoice := range page.Items {
+		page.Items[i] = invoiceInZone(invoice, loc)
+	}
+
+	return page, nil
+}
+
+// ChangeInvoiceStatus выставляет (sent) или аннулирует (void) счёт. При выставлении
+// счёт получает номер и дату issueDate, по умолчанию сегодняшнюю в часовом поясе
+// фотографа. Статус paid вручную не задаётся, он следует из оплат клиента.
+func (s *Service) ChangeInvoiceStatus(ctx context.Context, photographerID domain.PhotographerID, id domain.InvoiceID,
+	status string, issueDate *time.Time) error {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return err
+	}
+
+	invoice, err := s.ownedInvoice(ctx, photographerID, id)
+	if err != nil {
+		return err
+	}
+
+	var v domain.ValidationError
+	validateInvoiceStatus(&v, status)
+	if status == domain.InvoicePaid {
+		v.Add("status", "is set automatically when the client's payments cover the invoice")
+	}
+	if issueDate != nil && status != domain.InvoiceSent {
+		v.Add("issue_date", "is only allowed when the invoice is sent")
+	}
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	if status != domain.InvoiceSent {
+		return s.repo.SetInvoiceStatus(ctx, id, status)
+	}
+
+	if issueDate == nil {
+		loc, err := s.location(ctx, photographerID)
+		if err != nil {
+			return err
+		}
+		today := time.Now().In(loc)
+		date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+		issueDate = &date
+	}
+	if invoice.DueDate != nil && invoice.DueDate.Before(*issueDate) {
+		v.Add("issue_date", "must not be after the due date %s", invoice.DueDate.Format(time.DateOnly))
+	}
+	if err = s.validateClientForMoney(ctx, &v, photographerID, invoice.ClientID); err != nil {
+		return err
+	}
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.IssueInvoice(ctx, id, *issueDate)
+}
+
+// validateInvoice проверяет позиции счёта и приводит их цены к валюте фотографа.
+// Позиция с DebtID получает сумму и, если описание не задано, описание начисления
+// клиента, на которое ссылается.
+func (s *Service) validateInvoice(ctx context.Context, v *domain.ValidationError, invoice *domain.Invoice) error {
+	validateID(v, "photographer_id", invoice.PhotographerID)
+	if utf8.RuneCountInString(invoice.Note) > maxDescriptionLength {
+		v.Add("note", "must be at most %d characters", maxDescriptionLength)
+	}
+
+	switch {
+	case len(invoice.Items) == 0:
+		v.Add("items", "must not be empty")
+	case len(invoice.Items) > maxInvoiceItems:
+		v.Add("items", "must contain at most %d items", maxInvoiceItems)
+	}
+
+	photographer, err := s.repo.GetPhotographer(ctx, invoice.PhotographerID)
+	if err != nil {
+		return err
+	}
+	invoice.Total = domain.NewMoney(0, photographer.Currency)
+
+	debts, err := s.invoiceDebts(ctx, *invoice)
+	if err != nil {
+		return err
+	}
+	seen := make(map[domain.DebtID]bool)
+
+	for i := range invoice.Items {
+		item := &invoice.Items[i]
+		field := fmt.Sprintf("items[%d]", i)
+
+		item.Description = strings.TrimSpace(item.Description)
+		if item.DebtID != nil {
+			debt, ok := debts[*item.DebtID]
+			duplicate := seen[*item.DebtID]
+			seen[*item.DebtID] = true
+			filled := false
+
+			switch {
+			case !ok:
+				v.Add(field+".debt_id", "debt %d not found for this client", *item.DebtID)
+			case duplicate:
+				v.Add(field+".debt_id", "debt %d is already on this invoice", *item.DebtID)
+			case debt.Kind != domain.DebtKindCharge:
+				v.Add(field+".debt_id", "must reference a charge, not a reversal or adjustment")
+			case !debt.Amount.IsPositive():
+				v.Add(field+".debt_id", "debt %d is fully reversed", *item.DebtID)
+			case item.Quantity > 1:
+				v.Add(field+".quantity", "must be 1 for an item with debt_id")
+			case item.UnitPrice.Amount != 0 && item.UnitPrice.Amount != debt.Amount.Amount:
+				v.Add(field+".unit_price", "must be omitted or equal the debt amount %d", debt.Amount.Amount)
+			default:
+				filled = true
+				item.Quantity = 1
+				item.UnitPrice = debt.Amount
+				if item.Description == "" {
+					item.Description = debt.Description
+				}
+				if item.Description == "" {
+					item.Description = "Начисление от " + debt.OccurredAt.Format("02.01.2006")
+				}
+			}
+			if !filled {
+				continue
+			}
+		}
+
+		switch {
+		case item.Description == "":
+			v.Add(field+".description", "must not be empty")
+		case utf8.RuneCountInString(item.Description) > maxDescriptionLength:
+			v.Add(field+".description", "must be at most %d characters", maxDescriptionLength)
+		}
+
+		if item.Quantity < 1 || item.Quantity > maxInvoiceQuantity {
+			v.Add(field+".quantity", "must be between 1 and %d", maxInvoiceQuantity)
+		}
+
+		item.UnitPrice.Currency = normalizeCurrency(item.UnitPrice.Currency)
+		switch {
+		case !item.UnitPrice.IsPositive():
+			v.Add(field+".unit_price", "must be positive")
+		case item.UnitPrice.Currency == "":
+			item.UnitPrice.Currency = photographer.Currency
+		case item.UnitPrice.Currency != photographer.Currency:
+			v.Add(field+".currency", "must be %s, the photographer's currency", photographer.Currency)
+		}
+	}
+
+	invoice.Note = strings.TrimSpace(invoice.Note)
+
+	return nil
+}
+
+// invoiceDebts возвращает начисления клиента счёта, если на них ссылаются его
+// позиции. Сумма исходного начисления — вместе с его сторно и корректировками.
+func (s *Service) invoiceDebts(ctx context.Context, invoice domain.Invoice) (map[domain.DebtID]domain.DebtEntry, error) {
+	debts := make(map[domain.DebtID]domain.DebtEntry)
+	if !slices.ContainsFunc(invoice.Items, func(item domain.InvoiceItem) bool { return item.DebtID != nil }) {
+		return debts, nil
+	}
+
+	entries, err := s.repo.GetClientDebts(ctx, invoice.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.PhotographerID == invoice.PhotographerID {
+			debts[entry.ID] = entry
+		}
+	}
+	for _, entry := range entries {
+		if entry.ReversalOf == nil {
+			continue
+		}
+		if original, ok := debts[*entry.ReversalOf]; ok {
+			original.Amount.Amount += entry.Amount.Amount
+			debts[original.ID] = original
+		}
+	}
+
+	return debts, nil
+}
+
+func validateInvoiceStatus(v *domain.ValidationError, status string) {
+	switch status {
+	case domain.InvoiceDraft, domain.InvoiceSent, domain.InvoicePaid, domain.InvoiceVoid:
+	default:
+		v.Add("status", "must be one of draft, sent, paid, void")
+	}
+}
+
+// ownedInvoice возвращает счёт фотографа, чужой счёт неотличим от несуществующего.
+func (s *Service) ownedInvoice(ctx context.Context, photographerID domain.PhotographerID, id domain.InvoiceID) (domain.Invoice, error) {
+	invoice, err := s.repo.GetInvoice(ctx, id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+
+	if invoice.PhotographerID != photographerID {
+		return domain.Invoice{}, domain.NewError(domain.ErrNotFound, "invoice %d not found", id)
+	}
+
+	return invoice, nil
+}
+
+func invoiceInZone(invoice domain.Invoice, loc *time.Location) domain.Invoice {
+	invoice.CreatedAt = invoice.CreatedAt.In(loc)
+	invoice.UpdatedAt = invoice.UpdatedAt.In(loc)
+	return invoice
+}
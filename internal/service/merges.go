@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"photographer/internal/domain"
+	"strings"
+	"unicode/utf8"
+)
+
+// minDuplicateNameScore — триграммная близость имён, начиная с которой клиенты
+// считаются возможными дублями: «Екатерина Иванова» и «Катерина Иванова» проходят,
+// однофамильцы с разными именами — нет.
+const minDuplicateNameScore = 0.6
+
+// GetDuplicateCandidates возвращает пары клиентов фотографа, похожих на одного
+// человека, самые вероятные первыми.
+func (s *Service) GetDuplicateCandidates(ctx context.Context, photographerID domain.PhotographerID, limit int) ([]domain.DuplicateCandidate, error) {
+	var v domain.ValidationError
+	switch {
+	case limit == 0:
+		limit = defaultListLimit
+	case limit < 0 || limit > maxListLimit:
+		v.Add("limit", "must be between 1 and %d", maxListLimit)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.repo.GetDuplicateCandidates(ctx, photographerID, minDuplicateNameScore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, candidate := range candidates {
+		candidates[i].Client = clientInZone(candidate.Client, loc)
+		candidates[i].Duplicate = clientInZone(candidate.Duplicate, loc)
+	}
+
+	return candidates, nil
+}
+
+// MergeClients объединяет клиента sourceID с клиентом targetID: к targetID
+// переходят начисления, оплаты, съёмки и счета, а карточка дополняется
+// контактами, заметками и полями sourceID. Клиент sourceID удаляется.
+func (s *Service) MergeClients(ctx context.Context, photographerID domain.PhotographerID, targetID, sourceID domain.ClientID) (domain.ClientMerge, domain.Balance, error) {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+
+	var v domain.ValidationError
+	validateID(&v, "source_id", sourceID)
+	if sourceID == targetID {
+		v.Add("source_id", "must differ from the client being merged into")
+	}
+	if err := v.Err(); err != nil {
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+
+	target, err := s.ownedClient(ctx, photographerID, targetID)
+	if err != nil {
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+	source, err := s.ownedClient(ctx, photographerID, sourceID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			v.Add("source_id", "client %d not found", sourceID)
+			return domain.ClientMerge{}, domain.Balance{}, v.Err()
+		}
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+
+	if target.DeletedAt != nil {
+		return domain.ClientMerge{}, domain.Balance{}, domain.NewError(domain.ErrConflict, "client %d is deleted", targetID)
+	}
+	if source.DeletedAt != nil {
+		v.Add("source_id", "client is deleted")
+		return domain.ClientMerge{}, domain.Balance{}, v.Err()
+	}
+
+	merge, balance, err := s.repo.MergeClients(ctx, mergeProfiles(target, source), source)
+	if err != nil {
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.ClientMerge{}, domain.Balance{}, err
+	}
+	merge.MergedAt = merge.MergedAt.In(loc)
+
+	return merge, balance, nil
+}
+
+// GetClientMerges возвращает историю объединений клиента с его дублями.
+func (s *Service) GetClientMerges(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID) ([]domain.ClientMerge, error) {
+	if _, err := s.ownedClient(ctx, photographerID, clientID); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	merges, err := s.repo.GetClientMerges(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range merges {
+		merges[i].MergedAt = merges[i].MergedAt.In(loc)
+	}
+
+	return merges, nil
+}
+
+// mergeProfiles дополняет карточку target данными source: недостающими
+// контактами, датой рождения, источником, заметками и значениями своих полей.
+// Имя и заполненные поля target не меняются. Что не умещается в ограничения
+// карточки, остаётся в удалённой карточке source.
+func mergeProfiles(target, source domain.Client) domain.Client {
+	contacts := make([]domain.Contact, 0, len(target.Contacts)+len(source.Contacts))
+	contacts = append(contacts, target.Contacts...)
+	for _, contact := range source.Contacts {
+		if len(contacts) >= maxClientContacts {
+			break
+		}
+		if !hasContact(contacts, contact) {
+			contacts = append(contacts, contact)
+		}
+	}
+	target.Contacts = contacts
+
+	if target.Birthday == nil {
+		target.Birthday = source.Birthday
+	}
+	if target.Source == "" {
+		target.Source = source.Source
+	}
+
+	switch {
+	case target.Notes == "":
+		target.Notes = source.Notes
+	case source.Notes != "" && source.Notes != target.Notes:
+		target.Notes += "\n\n" + source.Notes
+	}
+	if utf8.RuneCountInString(target.Notes) > maxNotesLength {
+		target.Notes = string([]rune(target.Notes)[:maxNotesLength-1]) + "…"
+	}
+
+	customFields := make(map[string]any, len(target.CustomFields)+len(source.CustomFields))
+	for key, value := range source.CustomFields {
+		customFields[key] = value
+	}
+	for key, value := range target.CustomFields {
+		customFields[key] = value
+	}
+	target.CustomFields = customFields
+
+	return target
+}
+
+func hasContact(contacts []domain.Contact, contact domain.Contact) bool {
+	for _, c := range contacts {
+		if c.Type != contact.Type {
+			continue
+		}
+		if strings.EqualFold(c.Value, contact.Value) {
+			return true
+		}
+		isPhone := c.Type == domain.ContactPhone || c.Type == domain.ContactWhatsApp
+		if isPhone && sameNumber(c.Value, contact.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameNumber сравнивает телефоны по последним десяти цифрам, как и поиск дублей.
+func sameNumber(a, b string) bool {
+	a, b = onlyDigits(a), onlyDigits(b)
+	a, b = a[max(0, len(a)-10):], b[max(0, len(b)-10):]
+	return a != "" && a == b
+}
+
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"photographer/internal/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxPackageAddons       = 50
+	maxPackageDeliverables = 50
+	maxPackageDuration     = 7 * 24 * 60
+	maxChargeQuantity      = 1000
+)
+
+func (s *Service) CreatePackage(ctx context.Context, pkg domain.Package) (domain.PackageID, error) {
+	var v domain.ValidationError
+	if err := s.validatePackage(ctx, &v, &pkg); err != nil {
+		return 0, err
+	}
+	validateAddonIDs(&v, pkg.Addons, nil)
+	if err := v.Err(); err != nil {
+		return 0, err
+	}
+
+	return s.repo.CreatePackage(ctx, pkg)
+}
+
+// UpdatePackage заменяет пакет целиком. Опции с ID меняются на месте, опции без
+// ID добавляются, а не переданные уходят в архив: ID опций в проведённых
+// начислениях остаются действительными.
+func (s *Service) UpdatePackage(ctx context.Context, pkg domain.Package) error {
+	current, err := s.ownedPackage(ctx, pkg.PhotographerID, pkg.ID)
+	if err != nil {
+		return err
+	}
+
+	var v domain.ValidationError
+	if err = s.validatePackage(ctx, &v, &pkg); err != nil {
+		return err
+	}
+	validateAddonIDs(&v, pkg.Addons, current.Addons)
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdatePackage(ctx, pkg)
+}
+
+func (s *Service) ArchivePackage(ctx context.Context, photographerID domain.PhotographerID, id domain.PackageID) error {
+	if _, err := s.ownedPackage(ctx, photographerID, id); err != nil {
+		return err
+	}
+
+	return s.repo.ArchivePackage(ctx, id)
+}
+
+func (s *Service) GetPackage(ctx context.Context, photographerID domain.PhotographerID, id domain.PackageID) (domain.Package, error) {
+	pkg, err := s.ownedPackage(ctx, photographerID, id)
+	if err != nil {
+		return domain.Package{}, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Package{}, err
+	}
+
+	return packageInZone(pkg, loc), nil
+}
+
+func (s *Service) GetPackages(ctx context.Context, photographerID domain.PhotographerID, includeArchived bool) ([]domain.Package, error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, err := s.repo.GetPackages(ctx, photographerID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, pkg := range packages {
+		packages[i] = packageInZone(pkg, loc)
+	}
+
+	return packages, nil
+}
+
+// ChargePackage начисляет клиенту пакет по цене из прайс-листа: Quantity
+// пакетов и выбранные опции, каждая в своём количестве. Состав и цены
+// сохраняются в строках начисления. Без описания начисление получает описание
+// вида «Стандарт × 2; Доп. час × 1».
+func (s *Service) ChargePackage(ctx context.Context, charge domain.PackageCharge) (domain.DebtID, domain.Balance, error) {
+	pkg, err := s.ownedPackage(ctx, charge.PhotographerID, charge.PackageID)
+	if err != nil {
+		return 0, domain.Balance{}, err
+	}
+
+	var v domain.ValidationError
+	if pkg.ArchivedAt != nil {
+		v.Add("package_id", "package is archived")
+	}
+	if charge.Quantity == 0 {
+		charge.Quantity = 1
+	}
+	if charge.Quantity < 1 || charge.Quantity > maxChargeQuantity {
+		v.Add("quantity", "must be between 1 and %d", maxChargeQuantity)
+	}
+
+	addons := make(map[domain.AddonID]domain.Addon, len(pkg.Addons))
+	for _, addon := range pkg.Addons {
+		addons[addon.ID] = addon
+	}
+
+	lines := []domain.DebtLine{packageLine(nil, pkg.Name, charge.Quantity, pkg.Price)}
+	seen := make(map[domain.AddonID]bool, len(charge.Addons))
+	for i, selected := range charge.Addons {
+		field := fmt.Sprintf("addons[%d]", i)
+
+		addon, ok := addons[selected.ID]
+		switch {
+		case !ok:
+			v.Add(field+".id", "package has no addon %d", selected.ID)
+			continue
+		case seen[selected.ID]:
+			v.Add(field+".id", "addon %d is selected twice", selected.ID)
+			continue
+		}
+		seen[selected.ID] = true
+
+		if selected.Quantity == 0 {
+			selected.Quantity = 1
+		}
+		if selected.Quantity < 1 || selected.Quantity > maxChargeQuantity {
+			v.Add(field+".quantity", "must be between 1 and %d", maxChargeQuantity)
+			continue
+		}
+
+		lines = append(lines, packageLine(&addon.ID, addon.Name, selected.Quantity, addon.Price))
+	}
+	if err = v.Err(); err != nil {
+		return 0, domain.Balance{}, err
+	}
+
+	amount := domain.NewMoney(0, pkg.Price.Currency)
+	parts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		amount.Amount += line.Amount.Amount
+		parts = append(parts, fmt.Sprintf("%s × %d", line.Name, line.Quantity))
+	}
+
+	description := strings.TrimSpace(charge.Description)
+	if description == "" {
+		description = strings.Join(parts, "; ")
+		if utf8.RuneCountInString(description) > maxDescriptionLength {
+			description = string([]rune(description)[:maxDescriptionLength-1]) + "…"
+		}
+	}
+
+	return s.AddDebt(ctx, domain.DebtEntry{
+		PhotographerID: charge.PhotographerID,
+		ClientID:       charge.ClientID,
+		Amount:         amount,
+		Description:    description,
+		DueDate:        charge.DueDate,
+		PackageID:      &pkg.ID,
+		Lines:          lines,
+	})
+}
+
+func packageLine(addonID *domain.AddonID, name string, quantity int, price domain.Money) domain.DebtLine {
+	return domain.DebtLine{
+		AddonID:   addonID,
+		Name:      name,
+		Quantity:  quantity,
+		UnitPrice: price,
+		Amount:    domain.NewMoney(price.Amount*int64(quantity), price.Currency),
+	}
+}
+
+// validatePackage проверяет пакет и приводит цены к валюте фотографа. Пакет и
+// опции могут быть бесплатными.
+func (s *Service) validatePackage(ctx context.Context, v *domain.ValidationError, pkg *domain.Package) error {
+	validateID(v, "photographer_id", pkg.PhotographerID)
+	validateName(v, "name", pkg.Name)
+	pkg.Name = strings.TrimSpace(pkg.Name)
+	pkg.Description = strings.TrimSpace(pkg.Description)
+	if utf8.RuneCountInString(pkg.Description) > maxDescriptionLength {
+		v.Add("description", "must be at most %d characters", maxDescriptionLength)
+	}
+	if pkg.DurationMinutes < 0 || pkg.DurationMinutes > maxPackageDuration {
+		v.Add("duration_minutes", "must be between 0 and %d", maxPackageDuration)
+	}
+
+	if len(pkg.Deliverables) > maxPackageDeliverables {
+		v.Add("deliverables", "must contain at most %d items", maxPackageDeliverables)
+	}
+	deliverables := make([]string, 0, len(pkg.Deliverables))
+	for i, deliverable := range pkg.Deliverables {
+		deliverable = strings.TrimSpace(deliverable)
+		switch {
+		case deliverable == "":
+			v.Add(fmt.Sprintf("deliverables[%d]", i), "must not be empty")
+		case utf8.RuneCountInString(deliverable) > maxNameLength:
+			v.Add(fmt.Sprintf("deliverables[%d]", i), "must be at most %d characters", maxNameLength)
+		}
+		deliverables = append(deliverables, deliverable)
+	}
+	pkg.Deliverables = deliverables
+
+	photographer, err := s.repo.GetPhotographer(ctx, pkg.PhotographerID)
+	if err != nil {
+		return err
+	}
+
+	validatePrice(v, "price", "currency", &pkg.Price, photographer.Currency)
+
+	if len(pkg.Addons) > maxPackageAddons {
+		v.Add("addons", "must contain at most %d items", maxPackageAddons)
+	}
+	for i := range pkg.Addons {
+		addon := &pkg.Addons[i]
+		field := fmt.Sprintf("addons[%d]", i)
+
+		validateName(v, field+".name", addon.Name)
+		addon.Name = strings.TrimSpace(addon.Name)
+		validatePrice(v, field+".price", field+".currency", &addon.Price, photographer.Currency)
+	}
+
+	return nil
+}
+
+// validateAddonIDs проверяет, что опции с ID есть среди действующих опций пакета
+// и не повторяются. У нового пакета опций ещё нет.
+func validateAddonIDs(v *domain.ValidationError, addons, current []domain.Addon) {
+	known := make(map[domain.AddonID]bool, len(current))
+	for _, addon := range current {
+		known[addon.ID] = true
+	}
+
+	seen := make(map[domain.AddonID]bool, len(addons))
+	for i, addon := range addons {
+		if addon.ID == 0 {
+			continue
+		}
+
+		field := fmt.Sprintf("addons[%d].id", i)
+		switch {
+		case !known[addon.ID]:
+			v.Add(field, "package has no addon %d", addon.ID)
+		case seen[addon.ID]:
+			v.Add(field, "addon %d is listed twice", addon.ID)
+		}
+		seen[addon.ID] = true
+	}
+}
+
+// ownedPackage возвращает пакет фотографа, чужой пакет неотличим от несуществующего.
+func (s *Service) ownedPackage(ctx context.Context, photographerID domain.PhotographerID, id domain.PackageID) (domain.Package, error) {
+	pkg, err := s.repo.GetPackage(ctx, id)
+	if err != nil {
+		return domain.Package{}, err
+	}
+
+	if pkg.PhotographerID != photographerID {
+		return domain.Package{}, domain.NewError(domain.ErrNotFound, "package %d not found", id)
+	}
+
+	return pkg, nil
+}
+
+func packageInZone(pkg domain.Package, loc *time.Location) domain.Package {
+	pkg.CreatedAt = pkg.CreatedAt.In(loc)
+	pkg.UpdatedAt = pkg.UpdatedAt.In(loc)
+	if pkg.ArchivedAt != nil {
+		archivedAt := pkg.ArchivedAt.In(loc)
+		pkg.ArchivedAt = &archivedAt
+	}
+	return pkg
+}
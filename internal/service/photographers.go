@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"net/mail"
+	"photographer/internal/domain"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// taxIDPattern допускает ИНН и иностранные налоговые номера с буквами и дефисами.
+var taxIDPattern = regexp.MustCompile(`^[0-9A-Za-z\-]{1,32}$`)
+
+// UpdatePhotographer меняет в профиле фотографа только переданные в patch поля
+// и возвращает обновлённый профиль.
+func (s *Service) UpdatePhotographer(ctx context.Context, id domain.PhotographerID, patch domain.PhotographerPatch) (domain.Photographer, error) {
+	photographer, err := s.repo.GetPhotographer(ctx, id)
+	if err != nil {
+		return domain.Photographer{}, err
+	}
+
+	var v domain.ValidationError
+	if patch.Name != nil {
+		validateName(&v, "name", *patch.Name)
+		photographer.Name = strings.TrimSpace(*patch.Name)
+	}
+	for _, field := range []struct {
+		name  string
+		patch *string
+		value *string
+	}{
+		{"display_name", patch.DisplayName, &photographer.DisplayName},
+		{"legal_name", patch.LegalName, &photographer.LegalName},
+		{"tax_id", patch.TaxID, &photographer.TaxID},
+		{"email", patch.Email, &photographer.Email},
+		{"phone", patch.Phone, &photographer.Phone},
+	} {
+		if field.patch == nil {
+			continue
+		}
+		*field.value = strings.TrimSpace(*field.patch)
+		if utf8.RuneCountInString(*field.value) > maxNameLength {
+			v.Add(field.name, "must be at most %d characters", maxNameLength)
+		}
+	}
+	if patch.TimeZone != nil {
+		photographer.TimeZone = strings.TrimSpace(*patch.TimeZone)
+		if photographer.TimeZone == "" {
+			v.Add("time_zone", "must not be empty")
+		}
+		validateTimeZone(&v, "time_zone", photographer.TimeZone)
+	}
+	if patch.Currency != nil {
+		photographer.Currency = normalizeCurrency(*patch.Currency)
+		if photographer.Currency == "" {
+			v.Add("currency", "must not be empty")
+		}
+		validateCurrency(&v, "currency", photographer.Currency)
+	}
+	validateRequisites(&v, photographer)
+	if err = v.Err(); err != nil {
+		return domain.Photographer{}, err
+	}
+
+	if err = s.repo.UpdatePhotographer(ctx, photographer); err != nil {
+		return domain.Photographer{}, err
+	}
+
+	return s.GetPhotographer(ctx, id)
+}
+
+// DeactivatePhotographer оставляет фотографу доступ к истории только на чтение.
+func (s *Service) DeactivatePhotographer(ctx context.Context, id domain.PhotographerID) error {
+	return s.repo.DeactivatePhotographer(ctx, id)
+}
+
+// checkActive отклоняет изменения от имени деактивированного фотографа: его
+// начисления, оплаты, съёмки и счета доступны только на чтение.
+func (s *Service) checkActive(ctx context.Context, id domain.PhotographerID) error {
+	photographer, err := s.repo.GetPhotographer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if photographer.DeactivatedAt != nil {
+		return domain.NewError(domain.ErrForbidden, "photographer %d is deactivated", id)
+	}
+
+	return nil
+}
+
+// validateRequisites проверяет формат заполненных реквизитов и контактов фотографа.
+func validateRequisites(v *domain.ValidationError, photographer domain.Photographer) {
+	if photographer.TaxID != "" && !taxIDPattern.MatchString(photographer.TaxID) {
+		v.Add("tax_id", "must contain only letters, digits and dashes, at most 32 characters")
+	}
+	if photographer.Email != "" {
+		if address, err := mail.ParseAddress(photographer.Email); err != nil || address.Address != photographer.Email {
+			v.Add("email", "must be an email address")
+		}
+	}
+	if photographer.Phone != "" && !phonePattern.MatchString(photographer.Phone) {
+		v.Add("phone", "must be a phone number, e.g. +7 900 123-45-67")
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"photographer/internal/domain"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const maxReportBuckets = 1000
+
+// bucketLengths — примерная длина интервала отчёта, чтобы ограничить их число.
+var bucketLengths = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 31 * 24 * time.Hour,
+	"year":  366 * 24 * time.Hour,
+}
+
+// GetIncomeReport строит отчёт о доходах за период с разбивкой по интервалам
+// groupBy и по клиентам. Суммы считаются в базе в часовом поясе фотографа.
+func (s *Service) GetIncomeReport(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams, groupBy string) (domain.IncomeReport, error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.IncomeReport{}, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return domain.IncomeReport{}, err
+	}
+
+	var v domain.ValidationError
+	bucketLength, ok := bucketLengths[groupBy]
+	if !ok {
+		v.Add("group_by", "must be one of day, week, month, year")
+	}
+	if err = v.Err(); err != nil {
+		return domain.IncomeReport{}, err
+	}
+
+	// без From интервалы начинаются с первой оплаты, и ограничение считается от неё
+	from := params.From
+	if from == nil {
+		if from, err = s.repo.GetFirstIncomeAt(ctx, photographerID, params); err != nil {
+			return domain.IncomeReport{}, err
+		}
+	}
+	if from != nil {
+		to := time.Now()
+		if params.To != nil {
+			to = *params.To
+		}
+		if to.Sub(*from)/bucketLength > maxReportBuckets {
+			v.Add("group_by", "period is too long for %s buckets, at most %d are allowed, narrow it with from",
+				groupBy, maxReportBuckets)
+			return domain.IncomeReport{}, v.Err()
+		}
+	}
+
+	report := domain.IncomeReport{GroupBy: groupBy, From: params.From, To: time.Now().In(loc)}
+	if params.To != nil {
+		report.To = *params.To
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	eg.Go(func() error {
+		var err error
+		report.Total, report.Clients, err = s.repo.GetIncomeTotals(ctx, photographerID, params)
+		return err
+	})
+
+	eg.Go(func() error {
+		var err error
+		report.Buckets, err = s.repo.GetIncomeBuckets(ctx, photographerID, params, groupBy)
+		return err
+	})
+
+	if err = eg.Wait(); err != nil {
+		return domain.IncomeReport{}, err
+	}
+
+	for i := range report.Buckets {
+		report.Buckets[i].Start = report.Buckets[i].Start.In(loc)
+	}
+	if report.Clients == nil {
+		report.Clients = []domain.IncomeTotal{}
+	}
+
+	return report, nil
+}
+
+// GetAgingReport раскладывает задолженность клиентов по давности начислений на
+// конец дня asOf в часовом поясе фотографа, по умолчанию — на сегодня.
+func (s *Service) GetAgingReport(ctx context.Context, photographerID domain.PhotographerID, asOf *time.Time, includeDeleted bool) (domain.AgingReport, error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.AgingReport{}, err
+	}
+
+	day := time.Now().In(loc)
+	if asOf != nil {
+		day = wallClockIn(*asOf, loc)
+	}
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	total, clients, err := s.repo.GetAging(ctx, photographerID, day.AddDate(0, 0, 1), includeDeleted)
+	if err != nil {
+		return domain.AgingReport{}, err
+	}
+
+	if clients == nil {
+		clients = []domain.AgingTotal{}
+	}
+
+	return domain.AgingReport{AsOf: day.Format(time.DateOnly), Total: total, Clients: clients}, nil
+}
+
+// GetStatement строит выписку по клиенту за период. Границы периода берутся в
+// часовом поясе фотографа, без To выписка строится по текущий момент.
+func (s *Service) GetStatement(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, params domain.ListParams) (domain.Statement, error) {
+	client, err := s.ownedClient(ctx, photographerID, clientID)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return domain.Statement{}, err
+	}
+
+	to := time.Now().In(loc)
+	if params.To != nil {
+		to = *params.To
+	}
+
+	statement, err := s.repo.GetStatement(ctx, clientID, params.From, to)
+	if err != nil {
+		return domain.Statement{}, err
+	}
+
+	statement.ClientID, statement.ClientName = client.ID, client.Name
+	statement.From, statement.To = params.From, to
+	for i := range statement.Entries {
+		statement.Entries[i].OccurredAt = statement.Entries[i].OccurredAt.In(loc)
+	}
+
+	return statement, nil
+}
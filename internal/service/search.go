@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"photographer/internal/domain"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchLength    = 100
+
+	// minSearchScore отсекает случайные совпадения отдельных триграмм, но
+	// пропускает одну-две опечатки в слове.
+	minSearchScore = 0.3
+	// minPhoneDigits — столько цифр нужно в запросе, чтобы искать по телефонам.
+	minPhoneDigits = 3
+)
+
+// SearchClients ищет клиентов фотографа по имени, телефонам, email, другим
+// контактам и заметкам. Лучшие совпадения идут первыми.
+func (s *Service) SearchClients(ctx context.Context, photographerID domain.PhotographerID, query string, limit int) ([]domain.ClientMatch, error) {
+	var v domain.ValidationError
+	query = strings.TrimSpace(query)
+	switch {
+	case query == "":
+		v.Add("q", "must not be empty")
+	case utf8.RuneCountInString(query) > maxSearchLength:
+		v.Add("q", "must be at most %d characters", maxSearchLength)
+	}
+	switch {
+	case limit == 0:
+		limit = defaultSearchLimit
+	case limit < 0 || limit > maxSearchLimit:
+		v.Add("limit", "must be between 1 and %d", maxSearchLimit)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := phoneDigits(query)
+	matches, err := s.repo.SearchClients(ctx, photographerID, query, digits, minSearchScore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(query)
+	for i, match := range matches {
+		matches[i].Client = clientInZone(match.Client, loc)
+		matches[i].Highlights = highlights(match.Client, terms, digits)
+	}
+
+	return matches, nil
+}
+
+// phoneDigits возвращает цифры запроса, если он похож на номер телефона или
+// его часть, иначе пустую строку.
+func phoneDigits(query string) string {
+	var digits strings.Builder
+	for _, r := range query {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case strings.ContainsRune(" +-()", r):
+		default:
+			return ""
+		}
+	}
+
+	if digits.Len() < minPhoneDigits {
+		return ""
+	}
+	return digits.String()
+}
+
+// highlights возвращает поля клиента, в которых нашлись слова запроса.
+func highlights(client domain.Client, terms []string, digits string) []domain.Highlight {
+	result := []domain.Highlight{}
+	add := func(field, value string) {
+		if marked, ok := markTerms(value, terms); ok {
+			result = append(result, domain.Highlight{Field: field, Value: value, Marked: marked})
+		}
+	}
+
+	add("name", client.Name)
+	for i, contact := range client.Contacts {
+		field := fmt.Sprintf("contacts[%d].value", i)
+		isPhone := contact.Type == domain.ContactPhone || contact.Type == domain.ContactWhatsApp
+		if isPhone && digits != "" && strings.Contains(phoneDigits(contact.Value), digits) {
+			result = append(result, domain.Highlight{
+				Field:  field,
+				Value:  contact.Value,
+				Marked: "<mark>" + html.EscapeString(contact.Value) + "</mark>",
+			})
+			continue
+		}
+		add(field, contact.Value)
+	}
+	add("notes", client.Notes)
+
+	return result
+}
+
+// searchTerms разбивает запрос на слова в нижнем регистре. Однобуквенные слова
+// не подсвечиваются: они совпадают почти с любым текстом.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), isSeparator) {
+		if utf8.RuneCountInString(word) > 1 {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// markTerms экранирует текст для HTML и оборачивает в <mark> слова, похожие
+// на слова запроса.
+func markTerms(text string, terms []string) (string, bool) {
+	var (
+		b       strings.Builder
+		matched bool
+	)
+
+	rest := text
+	for rest != "" {
+		end := strings.IndexFunc(rest, isSeparator)
+		if end == 0 {
+			_, size := utf8.DecodeRuneInString(rest)
+			b.WriteString(html.EscapeString(rest[:size]))
+			rest = rest[size:]
+			continue
+		}
+		if end < 0 {
+			end = len(rest)
+		}
+
+		word := rest[:end]
+		if matchesTerm(strings.ToLower(word), terms) {
+			matched = true
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		rest = rest[end:]
+	}
+
+	return b.String(), matched
+}
+
+// matchesTerm сравнивает слово текста со словами запроса: слово совпадает,
+// если содержит слово запроса или его начало отличается от слова запроса
+// не больше чем на допустимое число опечаток.
+func matchesTerm(word string, terms []string) bool {
+	runes := []rune(word)
+	for _, term := range terms {
+		if strings.Contains(word, term) {
+			return true
+		}
+
+		termRunes := []rune(term)
+		typos := allowedTypos(len(termRunes))
+		if typos == 0 {
+			continue
+		}
+		prefix := runes[:min(len(runes), len(termRunes)+typos)]
+		if prefixDistance(prefix, termRunes) <= typos {
+			return true
+		}
+	}
+	return false
+}
+
+func allowedTypos(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// prefixDistance — наименьшее расстояние Левенштейна между term и началом
+// word любой длины: запрос может быть началом слова.
+func prefixDistance(word, term []rune) int {
+	prev := make([]int, len(word)+1)
+	curr := make([]int, len(word)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(term); i++ {
+		curr[0] = i
+		for j := 1; j <= len(word); j++ {
+			cost := 1
+			if term[i-1] == word[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	best := prev[0]
+	for _, d := range prev[1:] {
+		best = min(best, d)
+	}
+	return best
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -3,94 +3,495 @@ package service
 import (
 	"context"
 	"photographer/internal/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"golang.org/x/sync/errgroup"
 )
 
 type Repository interface {
-	CreatePhotographer(ctx context.Context, name string) (domain.PhotographerID, error)
-	GetPhotographers(ctx context.Context) ([]domain.Photographer, error)
+	CreatePhotographer(ctx context.Context, photographer domain.Photographer, passwordHash string) (domain.PhotographerID, error)
+	GetPhotographers(ctx context.Context, params domain.ListParams) (domain.Page[domain.PhotographerSummary], error)
+	GetPhotographer(ctx context.Context, id domain.PhotographerID) (domain.Photographer, error)
+	UpdatePhotographer(ctx context.Context, photographer domain.Photographer) error
+	DeactivatePhotographer(ctx context.Context, id domain.PhotographerID) error
+	GetPhotographerCredentials(ctx context.Context, login string) (domain.PhotographerID, string, error)
 
-	CreateClient(ctx context.Context, photographerID domain.PhotographerID, name string) (domain.ClientID, error)
-	UpdateClient(ctx context.Context, id domain.ClientID, name string) error
-	DeleteClient(ctx context.Context, id domain.ClientID) error
-	GetClients(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Client, error)
+	CreateAuthToken(ctx context.Context, tokenHash string, photographerID domain.PhotographerID, expiresAt time.Time) error
+	GetAuthToken(ctx context.Context, tokenHash string) (domain.AuthToken, error)
+	DeleteAuthToken(ctx context.Context, tokenHash string) error
+	DeleteExpiredAuthTokens(ctx context.Context) (int64, error)
 
-	AddDebt(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error
-	GetDebts(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Debt, error)
+	CreateClient(ctx context.Context, client domain.Client) (domain.ClientID, error)
+	UpdateClient(ctx context.Context, client domain.Client) error
+	DeleteClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error
+	GetClients(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error)
+	GetDeletedClients(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error)
+	RestoreClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error
+	PurgeDeletedClients(ctx context.Context, retention time.Duration, anonymize bool) (int64, error)
+	SearchClients(ctx context.Context, photographerID domain.PhotographerID, query, digits string, minScore float64, limit int) ([]domain.ClientMatch, error)
+	GetDuplicateCandidates(ctx context.Context, photographerID domain.PhotographerID, minNameScore float64, limit int) ([]domain.DuplicateCandidate, error)
+	MergeClients(ctx context.Context, target, source domain.Client) (domain.ClientMerge, domain.Balance, error)
+	GetClientMerges(ctx context.Context, clientID domain.ClientID) ([]domain.ClientMerge, error)
+	GetClient(ctx context.Context, id domain.ClientID) (domain.Client, error)
+	GetClientBalance(ctx context.Context, clientID domain.ClientID) (domain.Balance, error)
 
-	AddPayment(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error
-	GetPayments(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Payment, error)
-	GetPaymentsTotal(ctx context.Context, photographerID domain.PhotographerID) (int, error)
+	CreateClientField(ctx context.Context, field domain.ClientField) (domain.ClientFieldID, error)
+	UpdateClientField(ctx context.Context, field domain.ClientField) error
+	DeleteClientField(ctx context.Context, id domain.ClientFieldID) error
+	GetClientField(ctx context.Context, id domain.ClientFieldID) (domain.ClientField, error)
+	GetClientFields(ctx context.Context, photographerID domain.PhotographerID) ([]domain.ClientField, error)
+
+	AddDebt(ctx context.Context, debt domain.DebtEntry) (domain.DebtID, domain.Balance, error)
+	GetDebts(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Debt], error)
+	GetClientDebts(ctx context.Context, clientID domain.ClientID) ([]domain.DebtEntry, error)
+
+	AddPayment(ctx context.Context, photographerID domain.PhotographerID, payment domain.Payment) (domain.Balance, error)
+	VoidPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID, reason string) (domain.Balance, error)
+	RefundPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID, amount domain.Money, reason string) (domain.PaymentID, domain.Balance, error)
+	GetPayments(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Payment], error)
+	GetPaymentsTotals(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.PaymentTotals, error)
+	GetIncomeTotals(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.IncomeTotal, []domain.IncomeTotal, error)
+	GetIncomeBuckets(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams, groupBy string) ([]domain.IncomeBucket, error)
+	GetFirstIncomeAt(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (*time.Time, error)
+	GetStatement(ctx context.Context, clientID domain.ClientID, from *time.Time, to time.Time) (domain.Statement, error)
+	GetAging(ctx context.Context, photographerID domain.PhotographerID, asOf time.Time, includeDeleted bool) (domain.AgingTotal, []domain.AgingTotal, error)
+
+	CreateInvoice(ctx context.Context, invoice domain.Invoice) (domain.InvoiceID, error)
+	UpdateInvoice(ctx context.Context, invoice domain.Invoice) error
+	GetInvoice(ctx context.Context, id domain.InvoiceID) (domain.Invoice, error)
+	GetInvoices(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Invoice], error)
+	IssueInvoice(ctx context.Context, id domain.InvoiceID, issueDate time.Time) error
+	SetInvoiceStatus(ctx context.Context, id domain.InvoiceID, status string) error
+
+	CreateSession(ctx context.Context, session domain.Session) (domain.SessionID, error)
+	UpdateSession(ctx context.Context, session domain.Session) error
+	DeleteSession(ctx context.Context, id domain.SessionID) error
+	GetSession(ctx context.Context, id domain.SessionID) (domain.Session, error)
+	GetSessions(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Session], error)
+	SetSessionStatus(ctx context.Context, id domain.SessionID, status string) error
+	CreateSessions(ctx context.Context, sessions []domain.Session) ([]domain.SessionID, error)
+
+	SetCalendarToken(ctx context.Context, photographerID domain.PhotographerID, tokenHash string) error
+	GetCalendarPhotographer(ctx context.Context, tokenHash string) (domain.PhotographerID, error)
+	GetCalendarEvents(ctx context.Context, photographerID domain.PhotographerID, since time.Time) ([]domain.CalendarEvent, error)
+
+	CreatePackage(ctx context.Context, pkg domain.Package) (domain.PackageID, error)
+	UpdatePackage(ctx context.Context, pkg domain.Package) error
+	ArchivePackage(ctx context.Context, id domain.PackageID) error
+	GetPackage(ctx context.Context, id domain.PackageID) (domain.Package, error)
+	GetPackages(ctx context.Context, photographerID domain.PhotographerID, includeArchived bool) ([]domain.Package, error)
+
+	ReserveIdempotencyKey(ctx context.Context, record domain.IdempotencyRecord, ttl time.Duration) (domain.IdempotencyRecord, bool, error)
+	SaveIdempotencyResult(ctx context.Context, record domain.IdempotencyRecord) error
+	ReleaseIdempotencyKey(ctx context.Context, key, scope string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, ttl time.Duration) (int64, error)
+}
+
+type Options struct {
+	IdempotencyTTL time.Duration
+	AuthTokenTTL   time.Duration
+	// ClientRetention — сколько удалённый клиент хранится в корзине.
+	ClientRetention time.Duration
+	// AnonymizeClients — обезличивать клиентов после корзины вместо удаления.
+	AnonymizeClients bool
 }
 
 type Service struct {
 	repo Repository
+	opts Options
+}
+
+func New(repo Repository, opts Options) *Service {
+	return &Service{repo: repo, opts: opts}
+}
+
+// CreatePhotographer регистрирует фотографа с логином и паролем для входа в API.
+func (s *Service) CreatePhotographer(ctx context.Context, photographer domain.Photographer, password string) (domain.PhotographerID, error) {
+	var v domain.ValidationError
+	validateName(&v, "name", photographer.Name)
+	validateLogin(&v, "login", photographer.Login)
+	validatePassword(&v, "password", password)
+	validateTimeZone(&v, "time_zone", photographer.TimeZone)
+	photographer.Currency = normalizeCurrency(photographer.Currency)
+	validateCurrency(&v, "currency", photographer.Currency)
+	if err := v.Err(); err != nil {
+		return 0, err
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	photographer.Name = strings.TrimSpace(photographer.Name)
+	photographer.Login = strings.TrimSpace(photographer.Login)
+	if photographer.TimeZone == "" {
+		photographer.TimeZone = domain.DefaultTimeZone
+	}
+	if photographer.Currency == "" {
+		photographer.Currency = domain.DefaultCurrency
+	}
+
+	return s.repo.CreatePhotographer(ctx, photographer, passwordHash)
+}
+
+// GetPhotographers возвращает публичный список фотографов. Границы периода для
+// фильтра по дате регистрации берутся в UTC.
+func (s *Service) GetPhotographers(ctx context.Context, params domain.ListParams) (domain.Page[domain.PhotographerSummary], error) {
+	if err := validateListParams(&params, time.UTC); err != nil {
+		return domain.Page[domain.PhotographerSummary]{}, err
+	}
+
+	return s.repo.GetPhotographers(ctx, params)
+}
+
+// GetPhotographer возвращает фотографа с датой регистрации в его часовом поясе.
+func (s *Service) GetPhotographer(ctx context.Context, id domain.PhotographerID) (domain.Photographer, error) {
+	photographer, err := s.repo.GetPhotographer(ctx, id)
+	if err != nil {
+		return domain.Photographer{}, err
+	}
+
+	loc, err := loadLocation(photographer.TimeZone)
+	if err != nil {
+		return domain.Photographer{}, err
+	}
+	return photographerInZone(photographer, loc), nil
+}
+
+func (s *Service) CreateClient(ctx context.Context, client domain.Client) (domain.ClientID, error) {
+	var v domain.ValidationError
+	validateID(&v, "photographer_id", client.PhotographerID)
+	validateName(&v, "name", client.Name)
+	client.Name = strings.TrimSpace(client.Name)
+	if err := s.validateProfile(ctx, &v, &client, client.CustomFields); err != nil {
+		return 0, err
+	}
+	if err := v.Err(); err != nil {
+		return 0, err
+	}
+
+	return s.repo.CreateClient(ctx, client)
+}
+
+// UpdateClient меняет в карточке клиента только переданные в patch поля.
+func (s *Service) UpdateClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID, patch domain.ClientPatch) error {
+	client, err := s.ownedClient(ctx, photographerID, id)
+	if err != nil {
+		return err
+	}
+
+	var v domain.ValidationError
+	if patch.Name != nil {
+		validateName(&v, "name", *patch.Name)
+		client.Name = strings.TrimSpace(*patch.Name)
+	}
+	if patch.Contacts != nil {
+		client.Contacts = *patch.Contacts
+	}
+	switch {
+	case patch.ClearBirthday:
+		client.Birthday = nil
+	case patch.Birthday != nil:
+		client.Birthday = patch.Birthday
+	}
+	if patch.Source != nil {
+		client.Source = *patch.Source
+	}
+	if patch.Notes != nil {
+		client.Notes = *patch.Notes
+	}
+
+	if client.CustomFields == nil {
+		client.CustomFields = make(map[string]any, len(patch.CustomFields))
+	}
+	for key, value := range patch.CustomFields {
+		if value == nil {
+			delete(client.CustomFields, key)
+			continue
+		}
+		client.CustomFields[key] = value
+	}
+
+	if err = s.validateProfile(ctx, &v, &client, patch.CustomFields); err != nil {
+		return err
+	}
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateClient(ctx, client)
 }
 
-func New(repo Repository) *Service {
-	return &Service{repo: repo}
+func (s *Service) DeleteClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error {
+	return s.repo.DeleteClient(ctx, photographerID, id)
 }
 
-func (s *Service) CreatePhotographer(ctx context.Context, name string) (domain.PhotographerID, error) {
-	return s.repo.CreatePhotographer(ctx, name)
+func (s *Service) GetClients(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+
+	page, err := s.repo.GetClients(ctx, photographerID, params)
+	if err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+
+	for i, client := range page.Items {
+		page.Items[i] = clientInZone(client, loc)
+	}
+
+	return page, nil
 }
 
-func (s *Service) GetPhotographers(ctx context.Context) ([]domain.Photographer, error) {
-	return s.repo.GetPhotographers(ctx)
+func (s *Service) GetClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) (domain.Client, error) {
+	client, err := s.ownedClient(ctx, photographerID, id)
+	if err != nil {
+		return domain.Client{}, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Client{}, err
+	}
+
+	return clientInZone(client, loc), nil
 }
 
-func (s *Service) CreateClient(ctx context.Context, photographerID domain.PhotographerID, name string) (domain.ClientID, error) {
-	return s.repo.CreateClient(ctx, photographerID, name)
+func (s *Service) GetClientBalance(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID) (domain.Balance, error) {
+	if _, err := s.ownedClient(ctx, photographerID, clientID); err != nil {
+		return domain.Balance{}, err
+	}
+
+	return s.repo.GetClientBalance(ctx, clientID)
 }
 
-func (s *Service) UpdateClient(ctx context.Context, id domain.ClientID, name string) error {
-	return s.repo.UpdateClient(ctx, id, name)
+// AddDebt добавляет начисление и возвращает итоговый баланс клиента: накопленный
+// кредит автоматически уходит в погашение нового начисления.
+func (s *Service) AddDebt(ctx context.Context, debt domain.DebtEntry) (domain.DebtID, domain.Balance, error) {
+	if err := s.checkActive(ctx, debt.PhotographerID); err != nil {
+		return 0, domain.Balance{}, err
+	}
+
+	var v domain.ValidationError
+	validateID(&v, "photographer_id", debt.PhotographerID)
+	validateID(&v, "client_id", debt.ClientID)
+	if utf8.RuneCountInString(debt.Description) > maxDescriptionLength {
+		v.Add("description", "must be at most %d characters", maxDescriptionLength)
+	}
+	if err := s.validateMoney(ctx, &v, debt.PhotographerID, &debt.Amount); err != nil {
+		return 0, domain.Balance{}, err
+	}
+	if err := s.validateClientForMoney(ctx, &v, debt.PhotographerID, debt.ClientID); err != nil {
+		return 0, domain.Balance{}, err
+	}
+	if err := v.Err(); err != nil {
+		return 0, domain.Balance{}, err
+	}
+
+	debt.Description = strings.TrimSpace(debt.Description)
+
+	return s.repo.AddDebt(ctx, debt)
 }
 
-func (s *Service) DeleteClient(ctx context.Context, id domain.ClientID) error {
-	return s.repo.DeleteClient(ctx, id)
+func (s *Service) GetDebts(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Debt], error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Page[domain.Debt]{}, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return domain.Page[domain.Debt]{}, err
+	}
+
+	page, err := s.repo.GetDebts(ctx, photographerID, params)
+	if err != nil {
+		return domain.Page[domain.Debt]{}, err
+	}
+
+	for i := range page.Items {
+		page.Items[i].OccurredAt = page.Items[i].OccurredAt.In(loc)
+	}
+
+	return page, nil
 }
 
-func (s *Service) GetClients(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Client, error) {
-	return s.repo.GetClients(ctx, photographerID)
+func (s *Service) GetClientDebts(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID) ([]domain.DebtEntry, error) {
+	if _, err := s.ownedClient(ctx, photographerID, clientID); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	debts, err := s.repo.GetClientDebts(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range debts {
+		debts[i].OccurredAt = debts[i].OccurredAt.In(loc)
+	}
+
+	return debts, nil
 }
 
-func (s *Service) AddDebt(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error {
-	return s.repo.AddDebt(ctx, photographerID, clientID, amount)
+// AddPayment проводит оплату и возвращает итоговый баланс клиента: переплата
+// сохраняется как кредит. Оплату можно провести задним числом, но не будущим.
+func (s *Service) AddPayment(ctx context.Context, photographerID domain.PhotographerID, payment domain.Payment) (domain.Balance, error) {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return domain.Balance{}, err
+	}
+
+	var v domain.ValidationError
+	validateID(&v, "photographer_id", photographerID)
+	validateID(&v, "client_id", payment.ClientID)
+	if err := s.validateMoney(ctx, &v, photographerID, &payment.Amount); err != nil {
+		return domain.Balance{}, err
+	}
+	if err := s.validateClientForMoney(ctx, &v, photographerID, payment.ClientID); err != nil {
+		return domain.Balance{}, err
+	}
+
+	if payment.Method == "" {
+		payment.Method = domain.PaymentMethodOther
+	}
+	validatePaymentMethod(&v, "method", payment.Method)
+
+	payment.Reference = strings.TrimSpace(payment.Reference)
+	if utf8.RuneCountInString(payment.Reference) > maxNameLength {
+		v.Add("reference", "must be at most %d characters", maxNameLength)
+	}
+	payment.Note = strings.TrimSpace(payment.Note)
+	if utf8.RuneCountInString(payment.Note) > maxDescriptionLength {
+		v.Add("note", "must be at most %d characters", maxDescriptionLength)
+	}
+
+	now := time.Now()
+	switch {
+	case payment.OccurredAt.IsZero():
+		payment.OccurredAt = now
+	case payment.OccurredAt.After(now):
+		v.Add("occurred_at", "must not be in the future")
+	}
+
+	if err := v.Err(); err != nil {
+		return domain.Balance{}, err
+	}
+
+	return s.repo.AddPayment(ctx, photographerID, payment)
 }
 
-func (s *Service) GetDebts(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Debt, error) {
-	return s.repo.GetDebts(ctx, photographerID)
+// VoidPayment отменяет ошибочную оплату или возврат сторно и возвращает итоговый
+// баланс клиента. Причина обязательна: она остаётся в истории.
+func (s *Service) VoidPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID, reason string) (domain.Balance, error) {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return domain.Balance{}, err
+	}
+
+	var v domain.ValidationError
+	reason = strings.TrimSpace(reason)
+	switch {
+	case reason == "":
+		v.Add("reason", "must not be empty")
+	case utf8.RuneCountInString(reason) > maxDescriptionLength:
+		v.Add("reason", "must be at most %d characters", maxDescriptionLength)
+	}
+	if err := v.Err(); err != nil {
+		return domain.Balance{}, err
+	}
+
+	return s.repo.VoidPayment(ctx, photographerID, id, reason)
 }
 
-func (s *Service) AddPayment(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error {
-	return s.repo.AddPayment(ctx, photographerID, clientID, amount)
+// RefundPayment проводит возврат части оплаты или всей оплаты клиенту и
+// возвращает ID возврата и итоговый баланс клиента.
+func (s *Service) RefundPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID,
+	amount domain.Money, reason string) (domain.PaymentID, domain.Balance, error) {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return 0, domain.Balance{}, err
+	}
+
+	var v domain.ValidationError
+	if utf8.RuneCountInString(reason) > maxDescriptionLength {
+		v.Add("reason", "must be at most %d characters", maxDescriptionLength)
+	}
+	if err := s.validateMoney(ctx, &v, photographerID, &amount); err != nil {
+		return 0, domain.Balance{}, err
+	}
+	if err := v.Err(); err != nil {
+		return 0, domain.Balance{}, err
+	}
+
+	return s.repo.RefundPayment(ctx, photographerID, id, amount, strings.TrimSpace(reason))
 }
 
-func (s *Service) GetPayments(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Payment, int, error) {
+// GetPayments возвращает страницу оплат и их сумму по тем же фильтрам, в целом и
+// по способам оплаты. Возвраты и сторно входят в страницу и уменьшают сумму.
+func (s *Service) GetPayments(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Payment], domain.PaymentTotals, error) {
 	var (
-		payments []domain.Payment
-		total    int
+		page   domain.Page[domain.Payment]
+		totals domain.PaymentTotals
 	)
 
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return page, totals, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return page, totals, err
+	}
+
 	eg, ctx := errgroup.WithContext(ctx)
 
 	eg.Go(func() error {
 		var err error
-		payments, err = s.repo.GetPayments(ctx, photographerID)
+		page, err = s.repo.GetPayments(ctx, photographerID, params)
 		return err
 	})
 
 	eg.Go(func() error {
 		var err error
-		total, err = s.repo.GetPaymentsTotal(ctx, photographerID)
+		totals, err = s.repo.GetPaymentsTotals(ctx, photographerID, params)
 		return err
 	})
 
-	if err := eg.Wait(); err != nil {
-		return nil, 0, err
+	if err = eg.Wait(); err != nil {
+		return domain.Page[domain.Payment]{}, domain.PaymentTotals{}, err
+	}
+
+	for i, payment := range page.Items {
+		page.Items[i].OccurredAt = payment.OccurredAt.In(loc)
+		if payment.VoidedAt != nil {
+			voidedAt := payment.VoidedAt.In(loc)
+			page.Items[i].VoidedAt = &voidedAt
+		}
+	}
+
+	return page, totals, nil
+}
+
+// ownedClient возвращает клиента фотографа. Чужой клиент неотличим от
+// несуществующего, чтобы не раскрывать данные других фотографов.
+func (s *Service) ownedClient(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID) (domain.Client, error) {
+	client, err := s.repo.GetClient(ctx, clientID)
+	if err != nil {
+		return domain.Client{}, err
+	}
+
+	if client.PhotographerID != photographerID {
+		return domain.Client{}, domain.NewError(domain.ErrNotFound, "client %d not found", clientID)
 	}
 
-	return payments, total, nil
+	return client, nil
 }
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"photographer/internal/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxSessionDuration = 7 * 24 * time.Hour
+
+// CreateSession добавляет съёмку клиенту. Съёмка создаётся предварительной или
+// сразу подтверждённой, пересечение с другой съёмкой фотографа — конфликт.
+func (s *Service) CreateSession(ctx context.Context, session domain.Session) (domain.SessionID, error) {
+	if err := s.checkActive(ctx, session.PhotographerID); err != nil {
+		return 0, err
+	}
+
+	var v domain.ValidationError
+	validateID(&v, "client_id", session.ClientID)
+	if session.Status == "" {
+		session.Status = domain.SessionTentative
+	}
+	if session.Status != domain.SessionTentative && session.Status != domain.SessionConfirmed {
+		v.Add("status", "must be tentative or confirmed")
+	}
+	if err := s.validateSession(ctx, &v, &session); err != nil {
+		return 0, err
+	}
+	if err := s.validateClientForMoney(ctx, &v, session.PhotographerID, session.ClientID); err != nil {
+		return 0, err
+	}
+	if err := v.Err(); err != nil {
+		return 0, err
+	}
+
+	return s.repo.CreateSession(ctx, session)
+}
+
+func (s *Service) UpdateSession(ctx context.Context, session domain.Session) error {
+	if err := s.checkActive(ctx, session.PhotographerID); err != nil {
+		return err
+	}
+
+	if _, err := s.ownedSession(ctx, session.PhotographerID, session.ID); err != nil {
+		return err
+	}
+
+	var v domain.ValidationError
+	if err := s.validateSession(ctx, &v, &session); err != nil {
+		return err
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateSession(ctx, session)
+}
+
+func (s *Service) DeleteSession(ctx context.Context, photographerID domain.PhotographerID, id domain.SessionID) error {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return err
+	}
+
+	if _, err := s.ownedSession(ctx, photographerID, id); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSession(ctx, id)
+}
+
+func (s *Service) GetSession(ctx context.Context, photographerID domain.PhotographerID, id domain.SessionID) (domain.Session, error) {
+	session, err := s.ownedSession(ctx, photographerID, id)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	return sessionInZone(session, loc), nil
+}
+
+// GetSessions возвращает съёмки фотографа, пустой status — съёмки в любом статусе.
+func (s *Service) GetSessions(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Session], error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Page[domain.Session]{}, err
+	}
+
+	var v domain.ValidationError
+	if status != "" {
+		validateSessionStatus(&v, status)
+	}
+	if err = v.Err(); err != nil {
+		return domain.Page[domain.Session]{}, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return domain.Page[domain.Session]{}, err
+	}
+
+	page, err := s.repo.GetSessions(ctx, photographerID, status, params)
+	if err != nil {
+		return domain.Page[domain.Session]{}, err
+	}
+
+	for i, session := range page.Items {
+		page.Items[i] = sessionInZone(session, loc)
+	}
+
+	return page, nil
+}
+
+// ChangeSessionStatus подтверждает, проводит или отменяет съёмку.
+func (s *Service) ChangeSessionStatus(ctx context.Context, photographerID domain.PhotographerID, id domain.SessionID, status string) error {
+	if err := s.checkActive(ctx, photographerID); err != nil {
+		return err
+	}
+
+	session, err := s.ownedSession(ctx, photographerID, id)
+	if err != nil {
+		return err
+	}
+
+	var v domain.ValidationError
+	validateSessionStatus(&v, status)
+	if status == domain.SessionConfirmed {
+		if err = s.validateClientForMoney(ctx, &v, photographerID, session.ClientID); err != nil {
+			return err
+		}
+	}
+	if err = v.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.SetSessionStatus(ctx, id, status)
+}
+
+// validateSession проверяет время и описание съёмки и приводит цену к валюте
+// фотографа. Бесплатная съёмка допустима, она не начисляется клиенту.
+func (s *Service) validateSession(ctx context.Context, v *domain.ValidationError, session *domain.Session) error {
+	validateID(v, "photographer_id", session.PhotographerID)
+
+	switch {
+	case session.StartsAt.IsZero():
+		v.Add("starts_at", "must not be empty")
+	case session.EndsAt.IsZero():
+		v.Add("ends_at", "must not be empty")
+	case !session.EndsAt.After(session.StartsAt):
+		v.Add("ends_at", "must be after starts_at")
+	case session.EndsAt.Sub(session.StartsAt) > maxSessionDuration:
+		v.Add("ends_at", "session must be at most %d days long", int(maxSessionDuration.Hours()/24))
+	}
+
+	session.Location = strings.TrimSpace(session.Location)
+	if utf8.RuneCountInString(session.Location) > maxNameLength {
+		v.Add("location", "must be at most %d characters", maxNameLength)
+	}
+	session.Type = strings.TrimSpace(session.Type)
+	if utf8.RuneCountInString(session.Type) > maxNameLength {
+		v.Add("type", "must be at most %d characters", maxNameLength)
+	}
+	session.Note = strings.TrimSpace(session.Note)
+	if utf8.RuneCountInString(session.Note) > maxDescriptionLength {
+		v.Add("note", "must be at most %d characters", maxDescriptionLength)
+	}
+
+	photographer, err := s.repo.GetPhotographer(ctx, session.PhotographerID)
+	if err != nil {
+		return err
+	}
+
+	validatePrice(v, "price", "currency", &session.Price, photographer.Currency)
+
+	return nil
+}
+
+func validateSessionStatus(v *domain.ValidationError, status string) {
+	switch status {
+	case domain.SessionTentative, domain.SessionConfirmed, domain.SessionCompleted, domain.SessionCancelled:
+	default:
+		v.Add("status", "must be one of tentative, confirmed, completed, cancelled")
+	}
+}
+
+// ownedSession возвращает съёмку фотографа, чужая съёмка неотличима от несуществующей.
+func (s *Service) ownedSession(ctx context.Context, photographerID domain.PhotographerID, id domain.SessionID) (domain.Session, error) {
+	session, err := s.repo.GetSession(ctx, id)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	if session.PhotographerID != photographerID {
+		return domain.Session{}, domain.NewError(domain.ErrNotFound, "session %d not found", id)
+	}
+
+	return session, nil
+}
+
+func sessionInZone(session domain.Session, loc *time.Location) domain.Session {
+	session.StartsAt = session.StartsAt.In(loc)
+	session.EndsAt = session.EndsAt.In(loc)
+	session.CreatedAt = session.CreatedAt.In(loc)
+	session.UpdatedAt = session.UpdatedAt.In(loc)
+	return session
+}
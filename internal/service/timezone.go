@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"photographer/internal/domain"
+	"time"
+)
+
+// location возвращает часовой пояс фотографа, в котором отдаются его данные.
+func (s *Service) location(ctx context.Context, photographerID domain.PhotographerID) (*time.Location, error) {
+	photographer, err := s.repo.GetPhotographer(ctx, photographerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadLocation(photographer.TimeZone)
+}
+
+func loadLocation(timeZone string) (*time.Location, error) {
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone %q: %w", timeZone, err)
+	}
+
+	return loc, nil
+}
+
+func photographerInZone(photographer domain.Photographer, loc *time.Location) domain.Photographer {
+	photographer.CreatedAt = photographer.CreatedAt.In(loc)
+	photographer.UpdatedAt = photographer.UpdatedAt.In(loc)
+	if photographer.DeactivatedAt != nil {
+		deactivatedAt := photographer.DeactivatedAt.In(loc)
+		photographer.DeactivatedAt = &deactivatedAt
+	}
+	return photographer
+}
+
+func clientInZone(client domain.Client, loc *time.Location) domain.Client {
+	client.CreatedAt = client.CreatedAt.In(loc)
+	client.UpdatedAt = client.UpdatedAt.In(loc)
+	if client.DeletedAt != nil {
+		deletedAt := client.DeletedAt.In(loc)
+		client.DeletedAt = &deletedAt
+	}
+	return client
+}
+
+// wallClockIn возвращает момент с теми же датой и временем на часах, но в
+// часовом поясе loc.
+func wallClockIn(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
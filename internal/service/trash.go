@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"log"
+	"photographer/internal/domain"
+	"time"
+)
+
+// GetTrash возвращает удалённых клиентов фотографа, которых ещё можно
+// восстановить, недавно удалённых первыми.
+func (s *Service) GetTrash(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error) {
+	loc, err := s.location(ctx, photographerID)
+	if err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+
+	if err = validateListParams(&params, loc); err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+
+	page, err := s.repo.GetDeletedClients(ctx, photographerID, params)
+	if err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+
+	for i, client := range page.Items {
+		page.Items[i] = clientInZone(client, loc)
+	}
+
+	return page, nil
+}
+
+// RestoreClient возвращает клиента из корзины со всеми его записями.
+func (s *Service) RestoreClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error {
+	return s.repo.RestoreClient(ctx, photographerID, id)
+}
+
+// PurgeDeletedClients периодически удаляет или обезличивает клиентов, которые
+// пролежали в корзине дольше Options.ClientRetention, пока не отменён ctx.
+func (s *Service) PurgeDeletedClients(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.repo.PurgeDeletedClients(ctx, s.opts.ClientRetention, s.opts.AnonymizeClients)
+			if err != nil {
+				log.Printf("purge deleted clients error: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("purged %d deleted clients", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"photographer/internal/domain"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+
+	maxNameLength        = 255
+	maxDescriptionLength = 1000
+	maxLoginLength       = 64
+	minPasswordLength    = 8
+	maxPasswordLength    = 72 // ограничение bcrypt
+)
+
+func validateName(v *domain.ValidationError, field, name string) {
+	switch {
+	case strings.TrimSpace(name) == "":
+		v.Add(field, "must not be empty")
+	case utf8.RuneCountInString(name) > maxNameLength:
+		v.Add(field, "must be at most %d characters", maxNameLength)
+	}
+}
+
+func validateLogin(v *domain.ValidationError, field, login string) {
+	login = strings.TrimSpace(login)
+	switch {
+	case login == "":
+		v.Add(field, "must not be empty")
+	case utf8.RuneCountInString(login) > maxLoginLength:
+		v.Add(field, "must be at most %d characters", maxLoginLength)
+	case strings.ContainsFunc(login, unicode.IsSpace):
+		v.Add(field, "must not contain spaces")
+	}
+}
+
+func validatePassword(v *domain.ValidationError, field, password string) {
+	switch {
+	case utf8.RuneCountInString(password) < minPasswordLength:
+		v.Add(field, "must be at least %d characters", minPasswordLength)
+	case len(password) > maxPasswordLength:
+		v.Add(field, "must be at most %d bytes", maxPasswordLength)
+	}
+}
+
+// validateTimeZone принимает только имена IANA: "Local" зависит от сервера,
+// а пустое имя заменяется часовым поясом по умолчанию.
+func validateTimeZone(v *domain.ValidationError, field, timeZone string) {
+	if timeZone == "" {
+		return
+	}
+
+	if _, err := time.LoadLocation(timeZone); err != nil || timeZone == "Local" {
+		v.Add(field, "must be an IANA time zone name, e.g. %s", domain.DefaultTimeZone)
+	}
+}
+
+func validateCurrency(v *domain.ValidationError, field, currency string) {
+	if currency != "" && !domain.ValidCurrency(currency) {
+		v.Add(field, "must be an ISO 4217 currency code, e.g. %s", domain.DefaultCurrency)
+	}
+}
+
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+func validatePaymentMethod(v *domain.ValidationError, field, method string) {
+	switch method {
+	case domain.PaymentMethodCash, domain.PaymentMethodCard, domain.PaymentMethodBankTransfer,
+		domain.PaymentMethodSBP, domain.PaymentMethodOther:
+	default:
+		v.Add(field, "must be one of cash, card, bank_transfer, sbp, other")
+	}
+}
+
+func validateID[T ~int64](v *domain.ValidationError, field string, id T) {
+	if id <= 0 {
+		v.Add(field, "must be positive")
+	}
+}
+
+// validateClientForMoney проверяет, что по клиенту можно проводить денежные
+// операции: он принадлежит фотографу и не удалён.
+func (s *Service) validateClientForMoney(ctx context.Context, v *domain.ValidationError,
+	photographerID domain.PhotographerID, clientID domain.ClientID) error {
+	if photographerID <= 0 || clientID <= 0 {
+		return nil
+	}
+
+	client, err := s.repo.GetClient(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case client.PhotographerID != photographerID:
+		v.Add("client_id", "client does not belong to photographer %d", photographerID)
+	case client.DeletedAt != nil:
+		v.Add("client_id", "client is deleted")
+	}
+
+	return nil
+}
+
+// validateMoney проверяет сумму операции и подставляет валюту фотографа, если
+// она не указана: расчёты одного фотографа ведутся в одной валюте.
+func (s *Service) validateMoney(ctx context.Context, v *domain.ValidationError,
+	photographerID domain.PhotographerID, amount *domain.Money) error {
+	if !amount.IsPositive() {
+		v.Add("amount", "must be positive")
+	}
+
+	amount.Currency = normalizeCurrency(amount.Currency)
+	if photographerID <= 0 {
+		return nil
+	}
+
+	photographer, err := s.repo.GetPhotographer(ctx, photographerID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case amount.Currency == "":
+		amount.Currency = photographer.Currency
+	case amount.Currency != photographer.Currency:
+		v.Add("currency", "must be %s, the photographer's currency", photographer.Currency)
+	}
+
+	return nil
+}
+
+// validatePrice проверяет неотрицательную цену из прайс-листа и подставляет
+// валюту фотографа, если она не указана.
+func validatePrice(v *domain.ValidationError, field, currencyField string, price *domain.Money, currency string) {
+	if price.IsNegative() {
+		v.Add(field, "must not be negative")
+	}
+
+	price.Currency = normalizeCurrency(price.Currency)
+	switch {
+	case price.Currency == "":
+		price.Currency = currency
+	case price.Currency != currency:
+		v.Add(currencyField, "must be %s, the photographer's currency", currency)
+	}
+}
+
+// validateListParams проверяет параметры списка и переносит границы периода в
+// часовой пояс фотографа: даты из запроса приходят без зоны.
+func validateListParams(params *domain.ListParams, loc *time.Location) error {
+	var v domain.ValidationError
+
+	switch {
+	case params.Limit == 0:
+		params.Limit = defaultListLimit
+	case params.Limit < 0 || params.Limit > maxListLimit:
+		v.Add("limit", "must be between 1 and %d", maxListLimit)
+	}
+
+	if params.MinAmount < 0 {
+		v.Add("min_amount", "must not be negative")
+	}
+
+	if params.From != nil {
+		from := wallClockIn(*params.From, loc)
+		params.From = &from
+	}
+	if params.To != nil {
+		to := wallClockIn(*params.To, loc)
+		params.To = &to
+	}
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		v.Add("to", "must be after from")
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,129 @@
+package http_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+	"strings"
+)
+
+type contextKey int
+
+const photographerIDKey contextKey = iota
+
+// authenticated пропускает запрос только с действующим токеном и кладёт ID
+// фотографа в контекст запроса. Деактивированному фотографу доступно только
+// чтение.
+func (h *Handler) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return h.authenticate(next, false)
+}
+
+// authenticatedAny пропускает и изменяющие запросы деактивированного
+// фотографа, например выход.
+func (h *Handler) authenticatedAny(next http.HandlerFunc) http.HandlerFunc {
+	return h.authenticate(next, true)
+}
+
+func (h *Handler) authenticate(next http.HandlerFunc, allowDeactivated bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeProblem(w, r, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		stored, err := h.service.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, domain.ErrUnauthorized) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			} else {
+				log.Printf("authenticate error: %v", err)
+			}
+			writeError(w, r, err)
+			return
+		}
+
+		readOnly := r.Method == http.MethodGet || r.Method == http.MethodHead
+		if stored.Deactivated && !readOnly && !allowDeactivated {
+			writeError(w, r, domain.NewError(domain.ErrForbidden, "photographer %d is deactivated", stored.PhotographerID))
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), photographerIDKey, stored.PhotographerID)))
+	}
+}
+
+// principal возвращает ID аутентифицированного фотографа.
+func principal(r *http.Request) domain.PhotographerID {
+	id, _ := r.Context().Value(photographerIDKey).(domain.PhotographerID)
+	return id
+}
+
+// checkPhotographer запрещает обращаться к данным другого фотографа. Нулевой ID
+// означает, что клиент API не передал его явно.
+func checkPhotographer(r *http.Request, id domain.PhotographerID) error {
+	if id != 0 && id != principal(r) {
+		return domain.NewError(domain.ErrForbidden, "access to photographer %d is forbidden", id)
+	}
+	return nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// @Summary Выдаёт токен доступа по логину и паролю фотографа
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "Логин и пароль"
+// @Success 200 {object} domain.AuthToken "Токен доступа"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Неверный логин или пароль"
+// @Failure 500 {object} ProblemDetails
+// @Router /auth/login [post]
+func (h *Handler) loginHandler(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("json decode error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, err := h.service.Login(r.Context(), req.Login, req.Password)
+	if err != nil {
+		log.Printf("login error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, token)
+}
+
+// @Summary Отзывает текущий токен доступа
+// @Tags Auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} ProblemDetails
+// @Failure 500 {object} ProblemDetails
+// @Router /auth/logout [post]
+func (h *Handler) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	token, _ := bearerToken(r)
+
+	if err := h.service.Logout(r.Context(), token); err != nil {
+		log.Printf("logout error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
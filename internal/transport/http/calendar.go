@@ -0,0 +1,163 @@
+package http_handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const maxCalendarBytes = 1 << 20
+
+// @Summary Выдаёт новую ссылку на ленту съёмок для календаря
+// @Description Ссылка содержит секретный токен и открывается без аутентификации, поэтому
+// @Description её можно добавить в Google Calendar или Apple Calendar как подписку.
+// @Description Токен показывается только в этом ответе, прежняя ссылка перестаёт работать.
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Success 200 {object} CalendarTokenResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/calendar/token [post]
+func (h *Handler) rotateCalendarTokenHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	token, err := h.service.RotateCalendarToken(r.Context(), photographerID)
+	if err != nil {
+		log.Printf("rotate calendar token error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, CalendarTokenResponse{
+		Token: token,
+		URL:   absoluteURL(r, apiV2Prefix+"/calendar/"+token+".ics"),
+	})
+}
+
+// @Summary Отключает ленту съёмок для календаря
+// @Tags Calendar
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/calendar/token [delete]
+func (h *Handler) revokeCalendarTokenHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err = h.service.RevokeCalendarToken(r.Context(), photographerID); err != nil {
+		log.Printf("revoke calendar token error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Возвращает ленту съёмок в формате iCalendar
+// @Description Предстоящие съёмки и съёмки за последние 90 дней с клиентом, местом и долгом
+// @Description клиента в описании. Отменённые съёмки отдаются со статусом CANCELLED.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Токен ленты календаря"
+// @Success 200 {file} file
+// @Failure 404 {object} ProblemDetails "Лента не найдена или отключена"
+// @Failure 500 {object} ProblemDetails
+// @Router /calendar/{token}.ics [get]
+func (h *Handler) getCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.GetCalendarFeed(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		log.Printf("get calendar feed error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeCalendar(w, feed)
+}
+
+// @Summary Импортирует съёмки клиента из файла iCalendar
+// @Description Каждое событие файла становится предварительной съёмкой клиента без цены:
+// @Description SUMMARY — тип, LOCATION — место, DESCRIPTION — примечание. Отменённые события
+// @Description пропускаются, повторяющиеся не поддерживаются. Время без зоны считается временем
+// @Description фотографа. Съёмки добавляются все вместе или ни одна; номера событий в ошибках
+// @Description считаются с нуля среди неотменённых событий файла.
+// @Tags Calendar
+// @Security BearerAuth
+// @Accept text/calendar
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ"
+// @Param request body string true "Файл .ics"
+// @Success 200 {object} ImportSessionsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 409 {object} ProblemDetails "Съёмка пересекается с другой"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/sessions/import [post]
+func (h *Handler) importSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	photographer, err := h.service.GetPhotographer(r.Context(), photographerID)
+	if err != nil {
+		log.Printf("get photographer error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	loc, err := time.LoadLocation(photographer.TimeZone)
+	if err != nil {
+		log.Printf("load time zone error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	sessions, err := parseCalendar(http.MaxBytesReader(w, r.Body, maxCalendarBytes), loc)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	ids, err := h.service.ImportSessions(r.Context(), photographerID, clientID, sessions)
+	if err != nil {
+		log.Printf("import sessions error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, ImportSessionsResponse{IDs: ids})
+}
+
+// absoluteURL дополняет путь схемой и хостом запроса с учётом обратного прокси.
+func absoluteURL(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + r.Host + path
+}
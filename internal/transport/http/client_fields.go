@@ -0,0 +1,160 @@
+package http_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+)
+
+// @Summary Заводит своё поле карточки клиента
+// @Description Значения поля передаются в custom_fields клиента по ключу key: строки для text,
+// @Description select и date (YYYY-MM-DD), числа для number.
+// @Tags Clients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param request body ClientFieldRequest true "Поле карточки клиента"
+// @Success 200 {object} CreateClientFieldResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 409 {object} ProblemDetails "Поле с таким ключом уже есть"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/client-fields [post]
+func (h *Handler) createClientFieldHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req ClientFieldRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.service.CreateClientField(r.Context(), domain.ClientField{
+		PhotographerID: photographerID,
+		Key:            req.Key,
+		Label:          req.Label,
+		Type:           req.Type,
+		Options:        req.Options,
+	})
+	if err != nil {
+		log.Printf("create client field error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, CreateClientFieldResponse{ID: id})
+}
+
+// @Summary Меняет подпись и варианты своего поля карточки клиента
+// @Description Ключ и тип поля не меняются. Значения клиентов, которых нет среди новых
+// @Description вариантов, сохраняются.
+// @Tags Clients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param fid path int true "ID поля"
+// @Param request body ClientFieldRequest true "Подпись и варианты поля"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Поле не найдено"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/client-fields/{fid} [put]
+func (h *Handler) updateClientFieldHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, fieldID, err := clientFieldParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req ClientFieldRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.service.UpdateClientField(r.Context(), domain.ClientField{
+		ID:             fieldID,
+		PhotographerID: photographerID,
+		Label:          req.Label,
+		Options:        req.Options,
+	})
+	if err != nil {
+		log.Printf("update client field error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Удаляет своё поле карточки клиента
+// @Description Значения поля удаляются из карточек всех клиентов.
+// @Tags Clients
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param fid path int true "ID поля"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Поле не найдено"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/client-fields/{fid} [delete]
+func (h *Handler) deleteClientFieldHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, fieldID, err := clientFieldParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err = h.service.DeleteClientField(r.Context(), photographerID, fieldID); err != nil {
+		log.Printf("delete client field error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Возвращает свои поля карточки клиента
+// @Tags Clients
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Success 200 {object} GetClientFieldsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/client-fields [get]
+func (h *Handler) getClientFieldsHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	fields, err := h.service.GetClientFields(r.Context(), photographerID)
+	if err != nil {
+		log.Printf("get client fields error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if fields == nil {
+		fields = []domain.ClientField{}
+	}
+
+	encodeResponse(w, GetClientFieldsResponse{Items: fields})
+}
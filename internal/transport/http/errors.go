@@ -0,0 +1,86 @@
+package http_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+)
+
+const problemContentType = "application/problem+json"
+
+// ProblemDetails — тело ответа с ошибкой в формате RFC 7807.
+type ProblemDetails struct {
+	Type     string `json:"type" example:"about:blank"`
+	Title    string `json:"title" example:"Not Found"`
+	Status   int    `json:"status" example:"404"`
+	Detail   string `json:"detail,omitempty" example:"client 1 not found"`
+	Instance string `json:"instance,omitempty" example:"/clients/1"`
+
+	// Errors перечисляет ошибки по полям запроса для ответов 422.
+	Errors []domain.FieldError `json:"errors,omitempty"`
+}
+
+// writeError сопоставляет доменную ошибку с кодом ответа. Текст неизвестных
+// ошибок клиенту не отдаётся, чтобы не раскрывать детали хранилища.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var badRequest *badRequestError
+	if errors.As(err, &badRequest) {
+		writeProblem(w, r, http.StatusBadRequest, badRequest.message)
+		return
+	}
+
+	status := errorStatus(err)
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		writeProblemDetails(w, r, ProblemDetails{
+			Status: status,
+			Detail: "request validation failed",
+			Errors: validationErr.Fields,
+		})
+		return
+	}
+
+	detail := http.StatusText(status)
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		detail = domainErr.Message
+	}
+
+	writeProblem(w, r, status, detail)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	writeProblemDetails(w, r, ProblemDetails{Status: status, Detail: detail})
+}
+
+func writeProblemDetails(w http.ResponseWriter, r *http.Request, problem ProblemDetails) {
+	problem.Type = "about:blank"
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Printf("json encode error: %v", err)
+	}
+}
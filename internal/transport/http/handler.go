@@ -8,22 +8,78 @@ import (
 	"net/http"
 	"photographer/internal/domain"
 	"strconv"
+	"time"
 )
 
 type Service interface {
-	CreatePhotographer(ctx context.Context, name string) (domain.PhotographerID, error)
-	GetPhotographers(ctx context.Context) ([]domain.Photographer, error)
-
-	CreateClient(ctx context.Context, photographerID domain.PhotographerID, name string) (domain.ClientID, error)
-	UpdateClient(ctx context.Context, id domain.ClientID, name string) error
-	DeleteClient(ctx context.Context, id domain.ClientID) error
-	GetClients(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Client, error)
-
-	AddDebt(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error
-	GetDebts(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Debt, error)
-
-	AddPayment(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, amount int) error
-	GetPayments(ctx context.Context, photographerID domain.PhotographerID) ([]domain.Payment, int, error)
+	CreatePhotographer(ctx context.Context, photographer domain.Photographer, password string) (domain.PhotographerID, error)
+	GetPhotographers(ctx context.Context, params domain.ListParams) (domain.Page[domain.PhotographerSummary], error)
+	GetPhotographer(ctx context.Context, id domain.PhotographerID) (domain.Photographer, error)
+	UpdatePhotographer(ctx context.Context, id domain.PhotographerID, patch domain.PhotographerPatch) (domain.Photographer, error)
+	DeactivatePhotographer(ctx context.Context, id domain.PhotographerID) error
+
+	Login(ctx context.Context, login, password string) (domain.AuthToken, error)
+	Authenticate(ctx context.Context, token string) (domain.AuthToken, error)
+	Logout(ctx context.Context, token string) error
+
+	CreateClient(ctx context.Context, client domain.Client) (domain.ClientID, error)
+	UpdateClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID, patch domain.ClientPatch) error
+	DeleteClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error
+	GetClients(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error)
+	GetTrash(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Client], error)
+	RestoreClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) error
+	SearchClients(ctx context.Context, photographerID domain.PhotographerID, query string, limit int) ([]domain.ClientMatch, error)
+	GetDuplicateCandidates(ctx context.Context, photographerID domain.PhotographerID, limit int) ([]domain.DuplicateCandidate, error)
+	MergeClients(ctx context.Context, photographerID domain.PhotographerID, targetID, sourceID domain.ClientID) (domain.ClientMerge, domain.Balance, error)
+	GetClientMerges(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID) ([]domain.ClientMerge, error)
+	GetClient(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientID) (domain.Client, error)
+	GetClientBalance(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID) (domain.Balance, error)
+
+	AddDebt(ctx context.Context, debt domain.DebtEntry) (domain.DebtID, domain.Balance, error)
+	GetDebts(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Debt], error)
+	GetClientDebts(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID) ([]domain.DebtEntry, error)
+
+	AddPayment(ctx context.Context, photographerID domain.PhotographerID, payment domain.Payment) (domain.Balance, error)
+	VoidPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID, reason string) (domain.Balance, error)
+	RefundPayment(ctx context.Context, photographerID domain.PhotographerID, id domain.PaymentID, amount domain.Money, reason string) (domain.PaymentID, domain.Balance, error)
+	GetPayments(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams) (domain.Page[domain.Payment], domain.PaymentTotals, error)
+	GetIncomeReport(ctx context.Context, photographerID domain.PhotographerID, params domain.ListParams, groupBy string) (domain.IncomeReport, error)
+	GetStatement(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, params domain.ListParams) (domain.Statement, error)
+	GetAgingReport(ctx context.Context, photographerID domain.PhotographerID, asOf *time.Time, includeDeleted bool) (domain.AgingReport, error)
+
+	CreateInvoice(ctx context.Context, invoice domain.Invoice) (domain.InvoiceID, error)
+	UpdateInvoice(ctx context.Context, invoice domain.Invoice) error
+	GetInvoice(ctx context.Context, photographerID domain.PhotographerID, id domain.InvoiceID) (domain.Invoice, error)
+	GetInvoices(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Invoice], error)
+	ChangeInvoiceStatus(ctx context.Context, photographerID domain.PhotographerID, id domain.InvoiceID, status string, issueDate *time.Time) error
+
+	CreateSession(ctx context.Context, session domain.Session) (domain.SessionID, error)
+	UpdateSession(ctx context.Context, session domain.Session) error
+	DeleteSession(ctx context.Context, photographerID domain.PhotographerID, id domain.SessionID) error
+	GetSession(ctx context.Context, photographerID domain.PhotographerID, id domain.SessionID) (domain.Session, error)
+	GetSessions(ctx context.Context, photographerID domain.PhotographerID, status string, params domain.ListParams) (domain.Page[domain.Session], error)
+	ChangeSessionStatus(ctx context.Context, photographerID domain.PhotographerID, id domain.SessionID, status string) error
+
+	RotateCalendarToken(ctx context.Context, photographerID domain.PhotographerID) (string, error)
+	RevokeCalendarToken(ctx context.Context, photographerID domain.PhotographerID) error
+	GetCalendarFeed(ctx context.Context, token string) (domain.CalendarFeed, error)
+	ImportSessions(ctx context.Context, photographerID domain.PhotographerID, clientID domain.ClientID, sessions []domain.Session) ([]domain.SessionID, error)
+
+	CreatePackage(ctx context.Context, pkg domain.Package) (domain.PackageID, error)
+	UpdatePackage(ctx context.Context, pkg domain.Package) error
+	ArchivePackage(ctx context.Context, photographerID domain.PhotographerID, id domain.PackageID) error
+	GetPackage(ctx context.Context, photographerID domain.PhotographerID, id domain.PackageID) (domain.Package, error)
+	GetPackages(ctx context.Context, photographerID domain.PhotographerID, includeArchived bool) ([]domain.Package, error)
+	ChargePackage(ctx context.Context, charge domain.PackageCharge) (domain.DebtID, domain.Balance, error)
+
+	CreateClientField(ctx context.Context, field domain.ClientField) (domain.ClientFieldID, error)
+	UpdateClientField(ctx context.Context, field domain.ClientField) error
+	DeleteClientField(ctx context.Context, photographerID domain.PhotographerID, id domain.ClientFieldID) error
+	GetClientFields(ctx context.Context, photographerID domain.PhotographerID) ([]domain.ClientField, error)
+
+	ReserveIdempotencyKey(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error)
+	SaveIdempotencyResult(ctx context.Context, record domain.IdempotencyRecord) error
+	ReleaseIdempotencyKey(ctx context.Context, key, scope string) error
 }
 
 type Handler struct {
@@ -34,27 +90,135 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
+const apiV2Prefix = "/api/v2"
+
+// Маршруты v1 устарели с выходом v2, после v1Sunset их отключат.
+var (
+	v1Deprecated = time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	v1Sunset     = time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+)
+
 func (h *Handler) Handle() *mux.Router {
 	// Маршруты
 	router := mux.NewRouter()
 
+	h.handleV2(router.PathPrefix(apiV2Prefix).Subrouter())
+	h.handleV1(router)
+
+	return router
+}
+
+// handleV2 регистрирует ресурсные маршруты: всё, что принадлежит фотографу,
+// вложено в /photographers/{pid}.
+func (h *Handler) handleV2(router *mux.Router) {
+	// Аутентификация
+	router.HandleFunc("/auth/login", h.loginHandler).Methods("POST")
+	router.HandleFunc("/auth/logout", h.authenticatedAny(h.logoutHandler)).Methods("POST")
+
 	// Фотографы
-	router.HandleFunc("/photographers", h.createPhotographerHandler).Methods("POST")
-	router.HandleFunc("/photographers", h.getPhotographersHandler).Methods("GET")
+	router.HandleFunc("/photographers", h.createPhotographerHandler).Methods("POST") // регистрация, доступна без токена
+	router.HandleFunc("/photographers", h.authenticated(h.getPhotographersHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}", h.authenticated(h.getPhotographerHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}", h.authenticated(h.updatePhotographerHandler)).Methods("PATCH")
+	router.HandleFunc("/photographers/{pid}/deactivate", h.authenticated(h.deactivatePhotographerHandler)).Methods("POST")
 
 	// Клиенты
-	router.HandleFunc("/clients", h.createClientHandler).Methods("POST")
-	router.HandleFunc("/clients/{id}", h.updateClientHandler).Methods("PUT")
-	router.HandleFunc("/clients/{id}", h.deleteClientHandler).Methods("DELETE")
-	router.HandleFunc("/clients/{photographerID}", h.getClientsHandler).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients", h.authenticated(h.createClientHandler)).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/clients", h.authenticated(h.getClientsHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/search", h.authenticated(h.searchClientsHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/duplicates", h.authenticated(h.getDuplicateClientsHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/trash", h.authenticated(h.getTrashHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}", h.authenticated(h.getClientHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}", h.authenticated(h.updateClientHandler)).Methods("PUT", "PATCH")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}", h.authenticated(h.deleteClientHandler)).Methods("DELETE")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/balance", h.authenticated(h.getClientBalanceHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/restore", h.authenticated(h.restoreClientHandler)).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/merge", h.authenticated(h.idempotent(h.mergeClientsHandler))).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/merges", h.authenticated(h.getClientMergesHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/statement", h.authenticated(h.getStatementHandler)).Methods("GET")
 
 	// Операции с денежными средствами
-	router.HandleFunc("/debt", h.addDebtHandler).Methods("POST")                       // добавить сумму задолженности
-	router.HandleFunc("/payment", h.addPaymentHandler).Methods("POST")                 // провести оплату с обновлением задолженности
-	router.HandleFunc("/debtors/{photographerID}", h.getDebtorsHandler).Methods("GET") // список должников фотографа
-	router.HandleFunc("/incomes/{photographerID}", h.getIncomesHandler).Methods("GET") // операции и суммарный доход у фотографа
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/debts", h.authenticated(h.idempotent(h.addDebtHandler))).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/debts", h.authenticated(h.getClientDebtsHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/payments", h.authenticated(h.idempotent(h.addPaymentHandler))).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/payments/{payid}/void", h.authenticated(h.idempotent(h.voidPaymentHandler))).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/payments/{payid}/refunds", h.authenticated(h.idempotent(h.refundPaymentHandler))).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/debtors", h.authenticated(h.getDebtorsHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/debtors/aging", h.authenticated(h.getAgingHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/incomes", h.authenticated(h.getIncomesHandler)).Methods("GET")
+
+	// Счета
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/invoices", h.authenticated(h.idempotent(h.createInvoiceHandler))).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/invoices", h.authenticated(h.getInvoicesHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/invoices/{iid}", h.authenticated(h.getInvoiceHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/invoices/{iid}", h.authenticated(h.updateInvoiceHandler)).Methods("PUT")
+	router.HandleFunc("/photographers/{pid}/invoices/{iid}/status", h.authenticated(h.changeInvoiceStatusHandler)).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/invoices/{iid}/pdf", h.authenticated(h.getInvoicePDFHandler)).Methods("GET")
+
+	// Съёмки
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/sessions", h.authenticated(h.idempotent(h.createSessionHandler))).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/sessions", h.authenticated(h.getSessionsHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/sessions/{sid}", h.authenticated(h.getSessionHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/sessions/{sid}", h.authenticated(h.updateSessionHandler)).Methods("PUT")
+	router.HandleFunc("/photographers/{pid}/sessions/{sid}", h.authenticated(h.deleteSessionHandler)).Methods("DELETE")
+	router.HandleFunc("/photographers/{pid}/sessions/{sid}/status", h.authenticated(h.changeSessionStatusHandler)).Methods("POST")
+
+	// Календарь
+	router.HandleFunc("/calendar/{token}.ics", h.getCalendarFeedHandler).Methods("GET") // доступна по секретному токену ленты
+	router.HandleFunc("/photographers/{pid}/calendar/token", h.authenticated(h.rotateCalendarTokenHandler)).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/calendar/token", h.authenticated(h.revokeCalendarTokenHandler)).Methods("DELETE")
+	router.HandleFunc("/photographers/{pid}/clients/{cid}/sessions/import", h.authenticated(h.idempotent(h.importSessionsHandler))).Methods("POST")
+
+	// Прайс-лист
+	router.HandleFunc("/photographers/{pid}/packages", h.authenticated(h.createPackageHandler)).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/packages", h.authenticated(h.getPackagesHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/packages/{pkgid}", h.authenticated(h.getPackageHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/packages/{pkgid}", h.authenticated(h.updatePackageHandler)).Methods("PUT")
+	router.HandleFunc("/photographers/{pid}/packages/{pkgid}", h.authenticated(h.archivePackageHandler)).Methods("DELETE")
+
+	router.HandleFunc("/photographers/{pid}/client-fields", h.authenticated(h.createClientFieldHandler)).Methods("POST")
+	router.HandleFunc("/photographers/{pid}/client-fields", h.authenticated(h.getClientFieldsHandler)).Methods("GET")
+	router.HandleFunc("/photographers/{pid}/client-fields/{fid}", h.authenticated(h.updateClientFieldHandler)).Methods("PUT")
+	router.HandleFunc("/photographers/{pid}/client-fields/{fid}", h.authenticated(h.deleteClientFieldHandler)).Methods("DELETE")
+}
 
-	return router
+// handleV1 сохраняет исходные маршруты как устаревшие псевдонимы v2.
+func (h *Handler) handleV1(router *mux.Router) {
+	// Аутентификация
+	router.HandleFunc("/auth/login", deprecated(h.loginHandler)).Methods("POST")
+	router.HandleFunc("/auth/logout", deprecated(h.authenticatedAny(h.logoutHandler))).Methods("POST")
+
+	// Фотографы
+	router.HandleFunc("/photographers", deprecated(h.createPhotographerHandler)).Methods("POST")
+	router.HandleFunc("/photographers", deprecated(h.authenticated(h.getPhotographersHandler))).Methods("GET")
+
+	// Клиенты
+	router.HandleFunc("/clients", deprecated(h.authenticated(h.createClientHandler))).Methods("POST")
+	router.HandleFunc("/clients/{id}", deprecated(h.authenticated(h.updateClientHandler))).Methods("PUT")
+	router.HandleFunc("/clients/{id}", deprecated(h.authenticated(h.deleteClientHandler))).Methods("DELETE")
+	router.HandleFunc("/clients/{photographerID}", deprecated(h.authenticated(h.getClientsHandler))).Methods("GET")
+	router.HandleFunc("/clients/{id}/debts", deprecated(h.authenticated(h.getClientDebtsHandler))).Methods("GET")
+	router.HandleFunc("/clients/{id}/balance", deprecated(h.authenticated(h.getClientBalanceHandler))).Methods("GET")
+
+	// Операции с денежными средствами
+	router.HandleFunc("/debt", deprecated(h.authenticated(h.idempotent(h.addDebtHandler)))).Methods("POST")         // добавить начисление в журнал задолженностей
+	router.HandleFunc("/payment", deprecated(h.authenticated(h.idempotent(h.addPaymentHandler)))).Methods("POST")   // провести оплату с обновлением задолженности
+	router.HandleFunc("/debtors/{photographerID}", deprecated(h.authenticated(h.getDebtorsHandler))).Methods("GET") // список должников и клиентов с переплатой
+	router.HandleFunc("/incomes/{photographerID}", deprecated(h.authenticated(h.getIncomesHandler))).Methods("GET") // операции и суммарный доход у фотографа
+}
+
+// deprecated помечает ответы маршрутов v1 заголовками Deprecation (RFC 9745),
+// Sunset (RFC 8594) и Link на v2.
+func deprecated(next http.HandlerFunc) http.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(v1Deprecated.Unix(), 10)
+	sunset := v1Sunset.Format(http.TimeFormat)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", deprecation)
+		w.Header().Set("Sunset", sunset)
+		w.Header().Set("Link", "<"+apiV2Prefix+">; rel=\"successor-version\"")
+		next(w, r)
+	}
 }
 
 // @Summary Создаёт нового фотографа
@@ -63,281 +227,716 @@ func (h *Handler) Handle() *mux.Router {
 // @Produce json
 // @Param request body CreatePhotographerRequest true "Payload для создания фотографа"
 // @Success 200 {object} CreatePhotographerResponse "ID созданного фотографа"
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
+// @Failure 400 {object} ProblemDetails
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
 // @Router /photographers [post]
 func (h *Handler) createPhotographerHandler(w http.ResponseWriter, r *http.Request) {
 	var req CreatePhotographerRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("json decode error: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	id, err := h.service.CreatePhotographer(r.Context(), req.Name)
+	id, err := h.service.CreatePhotographer(r.Context(), domain.Photographer{
+		Name:     req.Name,
+		Login:    req.Login,
+		TimeZone: req.TimeZone,
+		Currency: req.Currency,
+	}, req.Password)
 	if err != nil {
 		log.Printf("create photographer error,: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
 	}
 
 	encodeResponse(w, CreatePhotographerResponse{ID: id})
 }
 
 // @Summary Возвращает список фотографов
+// @Description Только ID и имя для клиентов. Реквизиты и контакты фотографа отдаёт его профиль
+// @Description GET /photographers/{pid}, доступный только ему самому.
 // @Tags Photographers
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Success 200 {array} domain.Photographer
-// @Failure 500 {string} text/plain
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Сортировка: name (по имени для клиентов), date; '-' в начале — по убыванию" default(name)
+// @Param from query string false "Зарегистрированы не раньше даты (YYYY-MM-DD)"
+// @Param to query string false "Зарегистрированы не позже даты (YYYY-MM-DD)"
+// @Success 200 {object} GetPhotographersResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
 // @Router /photographers [get]
 func (h *Handler) getPhotographersHandler(w http.ResponseWriter, r *http.Request) {
-	photographers, err := h.service.GetPhotographers(r.Context())
+	params, err := listParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	photographers, err := h.service.GetPhotographers(r.Context(), params)
 	if err != nil {
 		log.Printf("get photographers error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	encodeResponse(w, photographers)
+	encodeResponse(w, GetPhotographersResponse{Items: photographers.Items, NextCursor: photographers.NextCursor})
 }
 
 // @Summary Создаёт нового клиента
 // @Tags Clients
+// @Security BearerAuth
 // @Accept json
 // @Produce json
+// @Param pid path int true "ID фотографа"
 // @Param request body CreateClientRequest true "Payload для создания клиента"
 // @Success 200 {object} CreateClientResponse "ID созданного клиента"
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /clients [post]
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Фотограф не найден"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients [post]
 func (h *Handler) createClientHandler(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		PhotographerID domain.PhotographerID `json:"photographer_id"`
-		Name           string                `json:"name"`
-	}
+	var req CreateClientRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("json decode error: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	photographerID, err := photographerParam(r, req.PhotographerID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	birthday, err := parseDate("birthday", req.Birthday)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	id, err := h.service.CreateClient(r.Context(), req.PhotographerID, req.Name)
+	id, err := h.service.CreateClient(r.Context(), domain.Client{
+		PhotographerID: photographerID,
+		Name:           req.Name,
+		Contacts:       req.Contacts,
+		Birthday:       birthday,
+		Source:         req.Source,
+		Notes:          req.Notes,
+		CustomFields:   req.CustomFields,
+	})
 	if err != nil {
 		log.Printf("create client error,: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
 	}
 
 	encodeResponse(w, CreateClientResponse{ID: id})
 }
 
+// @Summary Ищет клиентов фотографа
+// @Description Ищет неудалённых клиентов по имени, контактам и заметкам с учётом опечаток. Запрос из
+// @Description цифр ищет и по телефонам, записанным в любом формате. Лучшие совпадения идут первыми,
+// @Description у каждого — совпавшие поля, где найденные слова обёрнуты в <mark>.
+// @Tags Clients
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param q query string true "Строка поиска"
+// @Param limit query int false "Число результатов, по умолчанию 20, не больше 50"
+// @Success 200 {object} SearchClientsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/search [get]
+func (h *Handler) searchClientsHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	limit, err := intQuery[int](r, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	matches, err := h.service.SearchClients(r.Context(), photographerID, r.URL.Query().Get("q"), limit)
+	if err != nil {
+		log.Printf("search clients error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if matches == nil {
+		matches = []domain.ClientMatch{}
+	}
+
+	encodeResponse(w, SearchClientsResponse{Items: matches})
+}
+
+// @Summary Возвращает клиента фотографа
+// @Tags Clients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Success 200 {object} domain.Client
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid} [get]
+func (h *Handler) getClientHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	client, err := h.service.GetClient(r.Context(), photographerID, clientID)
+	if err != nil {
+		log.Printf("get client error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, client)
+}
+
 // @Summary Обновляет данные клиента
+// @Description Меняются только переданные поля карточки, остальные остаются прежними.
 // @Tags Clients
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param id path int true "ID клиента"
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
 // @Param request body UpdateClientRequest true "Payload для обновления клиента"
 // @Success 200
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /clients/{id} [put]
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid} [put]
+// @Router /photographers/{pid}/clients/{cid} [patch]
 func (h *Handler) updateClientHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	id, err := strconv.Atoi(vars["id"])
+	photographerID, clientID, err := clientParams(r, 0)
 	if err != nil {
-		log.Printf("convert id '%s' to int error: %v", vars["id"], err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	type updateClientRequest struct {
-		Name string `json:"name"`
-	}
-
-	var req updateClientRequest
+	var req UpdateClientRequest
 
 	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("json decode error: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err = h.service.UpdateClient(r.Context(), domain.ClientID(id), req.Name); err != nil {
+	patch := domain.ClientPatch{
+		Name:         req.Name,
+		Contacts:     req.Contacts,
+		Source:       req.Source,
+		Notes:        req.Notes,
+		CustomFields: req.CustomFields,
+	}
+	if req.Birthday != nil {
+		if patch.Birthday, err = parseDate("birthday", *req.Birthday); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		patch.ClearBirthday = patch.Birthday == nil
+	}
+
+	if err = h.service.UpdateClient(r.Context(), photographerID, clientID, patch); err != nil {
 		log.Printf("update client error,: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 	}
 }
 
 // @Summary Удаляет клиента по ID
 // @Tags Clients
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param id path int true "ID клиента"
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
 // @Success 200
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /clients/{id} [delete]
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid} [delete]
 func (h *Handler) deleteClientHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	id, err := strconv.Atoi(vars["id"])
+	photographerID, clientID, err := clientParams(r, 0)
 	if err != nil {
-		log.Printf("convert id '%s' to int error: %v", vars["id"], err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	if err = h.service.DeleteClient(r.Context(), domain.ClientID(id)); err != nil {
+	if err = h.service.DeleteClient(r.Context(), photographerID, clientID); err != nil {
 		log.Printf("delete client error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 	}
 }
 
 // @Summary Возвращает список клиентов фотографа
 // @Tags Clients
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param photographerID path int true "ID фотографа"
-// @Success 200 {array} domain.Client
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /clients/{photographerID} [get]
+// @Param pid path int true "ID фотографа"
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Сортировка: name, amount, date; '-' в начале — по убыванию" default(name)
+// @Param from query string false "Созданы не раньше даты (YYYY-MM-DD)"
+// @Param to query string false "Созданы не позже даты (YYYY-MM-DD)"
+// @Param min_amount query int false "Задолженность не меньше суммы в минимальных единицах валюты"
+// @Param include_deleted query bool false "Показывать удалённых клиентов"
+// @Success 200 {object} GetClientsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients [get]
 func (h *Handler) getClientsHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	photographerID, err := strconv.Atoi(vars["photographerID"])
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
 	if err != nil {
-		log.Printf("convert id '%s' to int error: %v", vars["id"], err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	clients, err := h.service.GetClients(r.Context(), domain.PhotographerID(photographerID))
+	clients, err := h.service.GetClients(r.Context(), photographerID, params)
 	if err != nil {
 		log.Printf("get clients error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	encodeResponse(w, clients)
+	encodeResponse(w, GetClientsResponse{Items: clients.Items, NextCursor: clients.NextCursor})
 }
 
-// @Summary Добавляет задолженность для клиента фотографа
+// @Summary Добавляет начисление в журнал задолженностей клиента
+// @Description С package_id сумма считается по прайс-листу: цена пакета, умноженная на quantity,
+// @Description плюс выбранные опции пакета. Начисление запоминает пакет, а в lines — количество,
+// @Description опции и цены на момент начисления.
 // @Tags Financial
+// @Security BearerAuth
 // @Accept json
 // @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
 // @Param request body AddDebtRequest true "Payload для добавления задолженности"
-// @Success 200
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /debt [post]
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} AddDebtResponse "ID созданного начисления и баланс клиента"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 409 {object} ProblemDetails "Запрос с этим ключом ещё выполняется"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации или ключ уже использован с другим телом запроса"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/debts [post]
 func (h *Handler) addDebtHandler(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		PhotographerID int `json:"photographer_id"`
-		ClientID       int `json:"client_id"`
-		Amount         int `json:"amount"`
-	}
+	var req AddDebtRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("decode request body error: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err := h.service.AddDebt(r.Context(), domain.PhotographerID(req.PhotographerID), domain.ClientID(req.ClientID), req.Amount); err != nil {
+	photographerID, err := photographerParam(r, domain.PhotographerID(req.PhotographerID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	_, clientID, err := clientParams(r, domain.ClientID(req.ClientID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var dueDate *time.Time
+	if req.DueDate != "" {
+		date, err := time.Parse(time.DateOnly, req.DueDate)
+		if err != nil {
+			log.Printf("parse due date '%s' error: %v", req.DueDate, err)
+			writeProblem(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		dueDate = &date
+	}
+
+	var (
+		id      domain.DebtID
+		balance domain.Balance
+	)
+	if req.PackageID != 0 {
+		id, balance, err = h.chargePackage(r, req, photographerID, clientID, dueDate)
+	} else {
+		id, balance, err = h.service.AddDebt(r.Context(), domain.DebtEntry{
+			PhotographerID: photographerID,
+			ClientID:       clientID,
+			Amount:         domain.NewMoney(req.Amount, req.Currency),
+			Description:    req.Description,
+			DueDate:        dueDate,
+		})
+	}
+	if err != nil {
 		log.Printf("add debt error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
+
+	encodeResponse(w, AddDebtResponse{ID: id, Balance: balance})
 }
 
-// @Summary Получает список должников фотографа
+// @Summary Возвращает историю начислений клиента
 // @Tags Financial
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param photographerID path int true "ID фотографа"
-// @Success 200 {array} domain.Debt "Список задолженностей"
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /debtors/{photographerID} [get]
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Success 200 {array} domain.DebtEntry "Журнал начислений"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/debts [get]
+func (h *Handler) getClientDebtsHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	debts, err := h.service.GetClientDebts(r.Context(), photographerID, clientID)
+	if err != nil {
+		log.Printf("get client debts error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, debts)
+}
+
+// @Summary Возвращает баланс клиента: задолженность или кредит
+// @Tags Financial
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Success 200 {object} domain.Balance "Баланс клиента"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/balance [get]
+func (h *Handler) getClientBalanceHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	balance, err := h.service.GetClientBalance(r.Context(), photographerID, clientID)
+	if err != nil {
+		log.Printf("get client balance error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, balance)
+}
+
+// @Summary Получает список должников фотографа и клиентов с переплатой
+// @Tags Financial
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Сортировка: name, amount, date; '-' в начале — по убыванию" default(-amount)
+// @Param from query string false "Последняя операция не раньше даты (YYYY-MM-DD)"
+// @Param to query string false "Последняя операция не позже даты (YYYY-MM-DD)"
+// @Param client_id query int false "ID клиента"
+// @Param min_amount query int false "Задолженность не меньше суммы в минимальных единицах валюты"
+// @Param include_deleted query bool false "Показывать удалённых клиентов"
+// @Success 200 {object} GetDebtorsResponse "Список задолженностей и кредитов"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/debtors [get]
 func (h *Handler) getDebtorsHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	photographerID, err := strconv.Atoi(vars["photographerID"])
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
 	if err != nil {
-		log.Printf("convert id '%s' to int error: %v", vars["id"], err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	debts, err := h.service.GetDebts(r.Context(), domain.PhotographerID(photographerID))
+	debts, err := h.service.GetDebts(r.Context(), photographerID, params)
 	if err != nil {
 		log.Printf("get debts error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	encodeResponse(w, debts)
+	encodeResponse(w, GetDebtorsResponse{Items: debts.Items, NextCursor: debts.NextCursor})
 }
 
 // @Summary Добавляет оплату клиента фотографу
 // @Tags Financial
+// @Security BearerAuth
 // @Accept json
 // @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
 // @Param request body AddPaymentRequest true "Payload для добавления оплаты"
-// @Success 200
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /payment [post]
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} AddPaymentResponse "Баланс клиента после оплаты"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 409 {object} ProblemDetails "Запрос с этим ключом ещё выполняется"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации или ключ уже использован с другим телом запроса"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/payments [post]
 func (h *Handler) addPaymentHandler(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		PhotographerID int `json:"photographer_id"`
-		ClientID       int `json:"client_id"`
-		Amount         int `json:"amount"`
-	}
+	var req AddPaymentRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Printf("decode request body error: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	photographerID, err := photographerParam(r, domain.PhotographerID(req.PhotographerID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	_, clientID, err := clientParams(r, domain.ClientID(req.ClientID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	occurredAt, err := parseTimestamp("occurred_at", req.OccurredAt)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.service.AddPayment(r.Context(), domain.PhotographerID(req.PhotographerID), domain.ClientID(req.ClientID), req.Amount); err != nil {
+	balance, err := h.service.AddPayment(r.Context(), photographerID, domain.Payment{
+		ClientID:   clientID,
+		Amount:     domain.NewMoney(req.Amount, req.Currency),
+		Method:     req.Method,
+		Reference:  req.Reference,
+		Note:       req.Note,
+		OccurredAt: occurredAt,
+	})
+	if err != nil {
 		log.Printf("add payment error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, AddPaymentResponse{Balance: balance})
+}
+
+// @Summary Отменяет ошибочную оплату или возврат
+// @Description Исходная операция остаётся в истории с отметкой voided_at, а сторно той же
+// @Description датой возвращает баланс клиента и доходы к состоянию без неё. Оплату с
+// @Description действующими возвратами отменить нельзя, сначала отменяются возвраты.
+// @Tags Financial
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param payid path int true "ID оплаты или возврата"
+// @Param request body VoidPaymentRequest true "Причина отмены"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} AddPaymentResponse "Баланс клиента после отмены"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Оплата не найдена"
+// @Failure 409 {object} ProblemDetails "Оплата уже отменена или по ней есть возвраты"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/payments/{payid}/void [post]
+func (h *Handler) voidPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, paymentID, err := paymentParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req VoidPaymentRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	balance, err := h.service.VoidPayment(r.Context(), photographerID, paymentID, req.Reason)
+	if err != nil {
+		log.Printf("void payment error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, AddPaymentResponse{Balance: balance})
+}
+
+// @Summary Проводит возврат по оплате
+// @Description Возврат уменьшает доход датой проведения и увеличивает задолженность
+// @Description клиента. Сумма возвратов по оплате не может превысить саму оплату.
+// @Tags Financial
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param payid path int true "ID оплаты"
+// @Param request body RefundPaymentRequest true "Сумма и причина возврата"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} RefundPaymentResponse "ID возврата и баланс клиента"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Оплата не найдена"
+// @Failure 409 {object} ProblemDetails "Оплата отменена или не является оплатой"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/payments/{payid}/refunds [post]
+func (h *Handler) refundPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, paymentID, err := paymentParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req RefundPaymentRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
+
+	id, balance, err := h.service.RefundPayment(r.Context(), photographerID, paymentID,
+		domain.NewMoney(req.Amount, req.Currency), req.Reason)
+	if err != nil {
+		log.Printf("refund payment error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, RefundPaymentResponse{ID: id, Balance: balance})
 }
 
 // @Summary Получает детализированный список доходов фотографа
 // @Tags Financial
+// @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param photographerID path int true "ID фотографа"
-// @Success 200 {object} GetIncomesResponse "Список платежей и общий доход"
-// @Failure 400 {string} text/plain
-// @Failure 500 {string} text/plain
-// @Router /incomes/{photographerID} [get]
+// @Param pid path int true "ID фотографа"
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Сортировка: amount, date; '-' в начале — по убыванию" default(-date)
+// @Param from query string false "Оплаты не раньше даты (YYYY-MM-DD)"
+// @Param to query string false "Оплаты не позже даты (YYYY-MM-DD)"
+// @Param client_id query int false "ID клиента"
+// @Param min_amount query int false "Движения по оплатам не меньше суммы по модулю в минимальных единицах валюты"
+// @Param include_deleted query bool false "Учитывать оплаты удалённых клиентов"
+// @Param group_by query string false "Отчёт по интервалам с разбивкой по клиентам и сравнением с предыдущим периодом" Enums(day, week, month, year)
+// @Success 200 {object} GetIncomesResponse "Список платежей, общий доход и доход по способам оплаты"
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/incomes [get]
 func (h *Handler) getIncomesHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	photographerID, err := strconv.Atoi(vars["photographerID"])
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
 	if err != nil {
-		log.Printf("convert id '%s' to int error: %v", vars["id"], err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	payments, total, err := h.service.GetPayments(r.Context(), domain.PhotographerID(photographerID))
+	payments, totals, err := h.service.GetPayments(r.Context(), photographerID, params)
 	if err != nil {
 		log.Printf("get payments error: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	resp := struct {
-		Payments []domain.Payment `json:"payments"`
-		Total    int              `json:"total"`
-	}{
-		Payments: payments,
-		Total:    total,
+	resp := GetIncomesResponse{
+		Items:      payments.Items,
+		NextCursor: payments.NextCursor,
+		Total:      totals.Total,
+		ByMethod:   totals.ByMethod,
+	}
+
+	if groupBy := r.URL.Query().Get("group_by"); groupBy != "" {
+		report, err := h.service.GetIncomeReport(r.Context(), photographerID, params, groupBy)
+		if err != nil {
+			log.Printf("get income report error: %v", err)
+			writeError(w, r, err)
+			return
+		}
+		resp.Report = &report
 	}
 
 	encodeResponse(w, resp)
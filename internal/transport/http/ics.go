@@ -0,0 +1,365 @@
+package http_handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icsTimeFormat   = "20060102T150405Z"
+	icsLocalFormat  = "20060102T150405"
+	icsDateFormat   = "20060102"
+	icsMaxLineBytes = 75
+)
+
+// encodeCalendar отдаёт ленту съёмок в формате iCalendar (RFC 5545). Время
+// пишется в UTC, поэтому описания часовых поясов в ленте не нужны.
+func encodeCalendar(w http.ResponseWriter, feed domain.CalendarFeed) {
+	var buf bytes.Buffer
+	line := func(name, value string) { writeICSLine(&buf, name+":"+value) }
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//photographer//sessions//RU")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICSText("Съёмки — "+feed.Photographer.Name))
+	line("X-WR-TIMEZONE", feed.Photographer.TimeZone)
+	line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	line("X-PUBLISHED-TTL", "PT1H")
+
+	for _, event := range feed.Events {
+		summary := "Съёмка: " + event.ClientName
+		if event.Type != "" {
+			summary += ", " + event.Type
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("session-%d@photographer", event.ID))
+		line("DTSTAMP", event.UpdatedAt.UTC().Format(icsTimeFormat))
+		line("LAST-MODIFIED", event.UpdatedAt.UTC().Format(icsTimeFormat))
+		line("DTSTART", event.StartsAt.UTC().Format(icsTimeFormat))
+		line("DTEND", event.EndsAt.UTC().Format(icsTimeFormat))
+		line("SUMMARY", escapeICSText(summary))
+		if event.Location != "" {
+			line("LOCATION", escapeICSText(event.Location))
+		}
+		line("DESCRIPTION", escapeICSText(calendarDescription(event)))
+		line("STATUS", icsStatus(event.Status))
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="sessions.ics"`)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("calendar write error: %v", err)
+	}
+}
+
+func calendarDescription(event domain.CalendarEvent) string {
+	lines := []string{"Клиент: " + event.ClientName}
+	if event.Type != "" {
+		lines = append(lines, "Тип: "+event.Type)
+	}
+	if event.Location != "" {
+		lines = append(lines, "Место: "+event.Location)
+	}
+	if event.Price.IsPositive() {
+		lines = append(lines, "Цена: "+event.Price.String())
+	}
+	if event.ClientBalance.Credit.IsPositive() {
+		lines = append(lines, "Переплата клиента: "+event.ClientBalance.Credit.String())
+	} else {
+		lines = append(lines, "Долг клиента: "+event.ClientBalance.Debt.String())
+	}
+	if event.Note != "" {
+		lines = append(lines, "", event.Note)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// icsStatus сопоставляет статус съёмки со статусом события: проведённая
+// съёмка в календаре остаётся подтверждённой.
+func icsStatus(status string) string {
+	switch status {
+	case domain.SessionTentative:
+		return "TENTATIVE"
+	case domain.SessionCancelled:
+		return "CANCELLED"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+// writeICSLine пишет строку с переносом по 75 байт: продолжение начинается с
+// пробела, многобайтовые символы не разрываются.
+func writeICSLine(buf *bytes.Buffer, line string) {
+	limit := icsMaxLineBytes
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineBytes - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+func escapeICSText(s string) string {
+	return icsTextEscaper.Replace(s)
+}
+
+// unescapeICSText разбирает экранирование текстовых значений, неизвестные
+// последовательности оставляет как есть.
+func unescapeICSText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		case '\\', ';', ',':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// icsProperty — свойство компонента iCalendar: имя, параметры и значение.
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseCalendar читает события из файла iCalendar и переводит их в съёмки без
+// фотографа, клиента и цены. Отменённые события пропускаются. Время без зоны,
+// даты целых дней и зоны, неизвестные базе IANA (например, имена Windows из
+// Outlook), считаются временем в часовом поясе фотографа loc.
+func parseCalendar(r io.Reader, loc *time.Location) ([]domain.Session, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &badRequestError{message: err.Error()}
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+
+	var (
+		v          domain.ValidationError
+		sessions   []domain.Session
+		components []string
+		props      []icsProperty
+	)
+	for _, raw := range strings.Split(text, "\n") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		prop, ok := parseICSProperty(raw)
+		if !ok {
+			return nil, &badRequestError{message: fmt.Sprintf("malformed iCalendar line %q", raw)}
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(prop.value))
+			if len(components) == 1 && components[0] != "VCALENDAR" {
+				return nil, &badRequestError{message: "body is not an iCalendar file"}
+			}
+			if components[len(components)-1] == "VEVENT" {
+				props = nil
+			}
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(prop.value) {
+				return nil, &badRequestError{message: fmt.Sprintf("unexpected END:%s", prop.value)}
+			}
+			if components[len(components)-1] == "VEVENT" {
+				if session, ok := icsSession(&v, len(sessions), props, loc); ok {
+					sessions = append(sessions, session)
+				}
+			}
+			components = components[:len(components)-1]
+		default:
+			// свойства вложенных компонентов, например напоминаний VALARM, не нужны
+			if len(components) > 0 && components[len(components)-1] == "VEVENT" {
+				props = append(props, prop)
+			}
+		}
+	}
+	if len(components) != 0 {
+		return nil, &badRequestError{message: "body is not a complete iCalendar file"}
+	}
+
+	if err = v.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// parseICSProperty разбирает строку вида NAME;PARAM=VALUE:VALUE. Двоеточие
+// внутри значения параметра в кавычках разделителем не считается.
+func parseICSProperty(line string) (icsProperty, bool) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return icsProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icsProperty{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return prop, true
+}
+
+// icsSession переводит свойства события в съёмку. Ошибки складываются в v с
+// номером события i среди импортируемых; ok=false у отменённого события, оно
+// не импортируется и не нумеруется.
+func icsSession(v *domain.ValidationError, i int, props []icsProperty, loc *time.Location) (domain.Session, bool) {
+	field := func(name string) string { return fmt.Sprintf("events[%d].%s", i, name) }
+
+	var (
+		session   domain.Session
+		allDay    bool
+		duration  *icsProperty
+		cancelled bool
+	)
+	for _, prop := range props {
+		switch prop.name {
+		case "DTSTART":
+			t, date, err := parseICSTime(prop, loc)
+			if err != nil {
+				v.Add(field("dtstart"), "%s", err)
+			}
+			session.StartsAt, allDay = t, date
+		case "DTEND":
+			t, _, err := parseICSTime(prop, loc)
+			if err != nil {
+				v.Add(field("dtend"), "%s", err)
+			}
+			session.EndsAt = t
+		case "DURATION":
+			duration = &prop
+		case "SUMMARY":
+			session.Type = unescapeICSText(prop.value)
+		case "LOCATION":
+			session.Location = unescapeICSText(prop.value)
+		case "DESCRIPTION":
+			session.Note = unescapeICSText(prop.value)
+		case "STATUS":
+			cancelled = strings.EqualFold(prop.value, "CANCELLED")
+		case "RRULE", "RDATE":
+			v.Add(field(strings.ToLower(prop.name)), "recurring events are not supported")
+		}
+	}
+	if cancelled {
+		return domain.Session{}, false
+	}
+
+	switch {
+	case !session.EndsAt.IsZero() || session.StartsAt.IsZero():
+	case duration != nil:
+		d, err := parseICSDuration(duration.value)
+		if err != nil {
+			v.Add(field("duration"), "%s", err)
+		}
+		session.EndsAt = session.StartsAt.Add(d)
+	case allDay:
+		session.EndsAt = session.StartsAt.AddDate(0, 0, 1)
+	}
+
+	return session, true
+}
+
+// parseICSTime разбирает DATE-TIME в UTC, с TZID или без зоны, а также DATE.
+// date сообщает, что значение — дата без времени.
+func parseICSTime(prop icsProperty, loc *time.Location) (t time.Time, date bool, err error) {
+	value := strings.TrimSpace(prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(icsDateFormat) {
+		t, err = time.ParseInLocation(icsDateFormat, value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("must be a date in YYYYMMDD format")
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(icsTimeFormat, value)
+	} else {
+		if tzid := prop.params["TZID"]; tzid != "" {
+			if tz, tzErr := time.LoadLocation(tzid); tzErr == nil && tzid != "Local" {
+				loc = tz
+			}
+		}
+		t, err = time.ParseInLocation(icsLocalFormat, value, loc)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("must be a date-time in YYYYMMDDTHHMMSS[Z] format")
+	}
+
+	return t, false, nil
+}
+
+var icsDurationPattern = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICSDuration разбирает неотрицательную длительность RFC 5545, например PT2H30M.
+func parseICSDuration(value string) (time.Duration, error) {
+	m := icsDurationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("must be a duration such as PT2H")
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("must be a duration such as PT2H")
+		}
+		d += time.Duration(n) * unit
+	}
+
+	return d, nil
+}
@@ -0,0 +1,128 @@
+package http_handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// idempotent сохраняет результат первого запроса с заголовком Idempotency-Key и
+// отдаёт его повторно на запросы с тем же ключом и телом. Повторное использование
+// ключа с другим телом отклоняется с 422.
+func (h *Handler) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			writeProblem(w, r, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+		if err != nil {
+			log.Printf("read request body error: %v", err)
+			writeProblem(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.Sum256(body)
+		record := domain.IdempotencyRecord{
+			Key:         key,
+			Scope:       fmt.Sprintf("%d %s %s", principal(r), r.Method, r.URL.Path),
+			RequestHash: hex.EncodeToString(hash[:]),
+		}
+
+		stored, created, err := h.service.ReserveIdempotencyKey(r.Context(), record)
+		if err != nil {
+			log.Printf("reserve idempotency key error: %v", err)
+			writeError(w, r, err)
+			return
+		}
+
+		if !created {
+			replayIdempotentResponse(w, r, record, stored)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		// результат сохраняем даже если клиент уже отключился
+		ctx := context.WithoutCancel(r.Context())
+
+		if rec.status >= http.StatusInternalServerError {
+			if err = h.service.ReleaseIdempotencyKey(ctx, record.Key, record.Scope); err != nil {
+				log.Printf("release idempotency key error: %v", err)
+			}
+			return
+		}
+
+		record.StatusCode = rec.status
+		record.ContentType = rec.Header().Get("Content-Type")
+		record.Body = rec.body.Bytes()
+		if err = h.service.SaveIdempotencyResult(ctx, record); err != nil {
+			log.Printf("save idempotency result error: %v", err)
+		}
+	}
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, r *http.Request, record, stored domain.IdempotencyRecord) {
+	if stored.RequestHash != record.RequestHash {
+		writeProblem(w, r, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different payload")
+		return
+	}
+
+	if stored.StatusCode == 0 {
+		writeProblem(w, r, http.StatusConflict, "request with this Idempotency-Key is still in progress")
+		return
+	}
+
+	if stored.ContentType != "" {
+		w.Header().Set("Content-Type", stored.ContentType)
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	if _, err := w.Write(stored.Body); err != nil {
+		log.Printf("write replayed response error: %v", err)
+	}
+}
+
+// responseRecorder пропускает ответ клиенту и одновременно запоминает его.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,295 @@
+package http_handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+)
+
+// @Summary Создаёт черновик счёта клиенту
+// @Description Номер счёта и начисление клиенту появляются только при выставлении счёта.
+// @Description Позиция с debt_id выставляет уже проведённое начисление клиента, например за
+// @Description съёмку или пакет, и повторно его не начисляет.
+// @Tags Invoices
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ"
+// @Param request body InvoiceRequest true "Позиции и срок оплаты счёта"
+// @Success 200 {object} CreateInvoiceResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/invoices [post]
+func (h *Handler) createInvoiceHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req InvoiceRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invoice, err := req.invoice()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	invoice.PhotographerID = photographerID
+	invoice.ClientID = clientID
+
+	id, err := h.service.CreateInvoice(r.Context(), invoice)
+	if err != nil {
+		log.Printf("create invoice error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, CreateInvoiceResponse{ID: id})
+}
+
+// @Summary Заменяет содержимое черновика счёта
+// @Tags Invoices
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param iid path int true "ID счёта"
+// @Param request body InvoiceRequest true "Позиции и срок оплаты счёта"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Счёт не найден"
+// @Failure 409 {object} ProblemDetails "Счёт уже выставлен"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/invoices/{iid} [put]
+func (h *Handler) updateInvoiceHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, invoiceID, err := invoiceParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req InvoiceRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invoice, err := req.invoice()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	invoice.ID = invoiceID
+	invoice.PhotographerID = photographerID
+
+	if err = h.service.UpdateInvoice(r.Context(), invoice); err != nil {
+		log.Printf("update invoice error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Возвращает счёт с позициями
+// @Tags Invoices
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param iid path int true "ID счёта"
+// @Success 200 {object} domain.Invoice
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Счёт не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/invoices/{iid} [get]
+func (h *Handler) getInvoiceHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, invoiceID, err := invoiceParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	invoice, err := h.service.GetInvoice(r.Context(), photographerID, invoiceID)
+	if err != nil {
+		log.Printf("get invoice error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, invoice)
+}
+
+// @Summary Возвращает счета фотографа без позиций
+// @Tags Invoices
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param status query string false "Статус счёта" Enums(draft, sent, paid, void)
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Сортировка: amount, date; '-' в начале — по убыванию" default(-date)
+// @Param from query string false "Созданы не раньше даты (YYYY-MM-DD)"
+// @Param to query string false "Созданы не позже даты (YYYY-MM-DD)"
+// @Param client_id query int false "Только счета клиента"
+// @Param min_amount query int false "Сумма счёта не меньше суммы в минимальных единицах валюты"
+// @Param include_deleted query bool false "Показывать счета удалённых клиентов"
+// @Success 200 {object} GetInvoicesResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/invoices [get]
+func (h *Handler) getInvoicesHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	invoices, err := h.service.GetInvoices(r.Context(), photographerID, r.URL.Query().Get("status"), params)
+	if err != nil {
+		log.Printf("get invoices error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, GetInvoicesResponse{Items: invoices.Items, NextCursor: invoices.NextCursor})
+}
+
+// @Summary Меняет статус счёта
+// @Description Черновик можно выставить (sent) или аннулировать (void), выставленный и ещё
+// @Description не оплаченный счёт — аннулировать. При выставлении счёт получает следующий номер
+// @Description фотографа за год, а позиции без debt_id начисляются клиенту; аннулирование
+// @Description сторнирует эти начисления, уже проведённые до счёта начисления остаются.
+// @Description Статус paid вручную не задаётся: счёт оплачен, когда оплаты клиента покрывают
+// @Description все его начисления вместе с более ранними.
+// @Tags Invoices
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param iid path int true "ID счёта"
+// @Param request body ChangeInvoiceStatusRequest true "Новый статус"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Счёт не найден"
+// @Failure 409 {object} ProblemDetails "Переход из текущего статуса невозможен"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/invoices/{iid}/status [post]
+func (h *Handler) changeInvoiceStatusHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, invoiceID, err := invoiceParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req ChangeInvoiceStatusRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	issueDate, err := parseDate("issue_date", req.IssueDate)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err = h.service.ChangeInvoiceStatus(r.Context(), photographerID, invoiceID, req.Status, issueDate); err != nil {
+		log.Printf("change invoice status error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Возвращает счёт в PDF
+// @Description Черновик печатается без номера с пометкой «Черновик».
+// @Tags Invoices
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param pid path int true "ID фотографа"
+// @Param iid path int true "ID счёта"
+// @Success 200 {file} file
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Счёт не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/invoices/{iid}/pdf [get]
+func (h *Handler) getInvoicePDFHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, invoiceID, err := invoiceParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	invoice, err := h.service.GetInvoice(r.Context(), photographerID, invoiceID)
+	if err != nil {
+		log.Printf("get invoice error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	photographer, err := h.service.GetPhotographer(r.Context(), photographerID)
+	if err != nil {
+		log.Printf("get photographer error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	filename := fmt.Sprintf("invoice-draft-%d.pdf", invoice.ID)
+	if invoice.Number != "" {
+		filename = fmt.Sprintf("invoice-%s.pdf", invoice.Number)
+	}
+
+	encodePDF(w, r, filename, invoicePDF(photographer, invoice))
+}
+
+// invoice переводит тело запроса в счёт без фотографа и клиента.
+func (req InvoiceRequest) invoice() (domain.Invoice, error) {
+	dueDate, err := parseDate("due_date", req.DueDate)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+
+	invoice := domain.Invoice{DueDate: dueDate, Note: req.Note}
+	for _, item := range req.Items {
+		invoice.Items = append(invoice.Items, domain.InvoiceItem{
+			DebtID:      item.DebtID,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   domain.NewMoney(item.UnitPrice, item.Currency),
+		})
+	}
+
+	return invoice, nil
+}
@@ -0,0 +1,130 @@
+package http_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+)
+
+// @Summary Возвращает возможные дубли клиентов
+// @Description Пары клиентов с общим телефоном, общим email или похожими именами, самые вероятные
+// @Description первыми. Телефоны сравниваются по последним десяти цифрам.
+// @Tags Clients
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param limit query int false "Число пар, по умолчанию 50, не больше 200"
+// @Success 200 {object} GetDuplicateClientsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/duplicates [get]
+func (h *Handler) getDuplicateClientsHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	limit, err := intQuery[int](r, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	candidates, err := h.service.GetDuplicateCandidates(r.Context(), photographerID, limit)
+	if err != nil {
+		log.Printf("get duplicate clients error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if candidates == nil {
+		candidates = []domain.DuplicateCandidate{}
+	}
+
+	encodeResponse(w, GetDuplicateClientsResponse{Items: candidates})
+}
+
+// @Summary Объединяет клиента с его дублем
+// @Description Начисления, оплаты, съёмки и счета клиента source_id переходят к клиенту из пути в одной
+// @Description транзакции. Карточка дополняется контактами, датой рождения, источником, заметками и
+// @Description полями дубля, имя не меняется. Дубль удаляется, объединение попадает в историю клиента.
+// @Tags Clients
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента, который остаётся"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ"
+// @Param request body MergeClientsRequest true "Дубль, который поглощается"
+// @Success 200 {object} MergeClientsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 409 {object} ProblemDetails "Клиент удалён или изменён во время объединения"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/merge [post]
+func (h *Handler) mergeClientsHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req MergeClientsRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merge, balance, err := h.service.MergeClients(r.Context(), photographerID, clientID, req.SourceID)
+	if err != nil {
+		log.Printf("merge clients error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, MergeClientsResponse{Merge: merge, Balance: balance})
+}
+
+// @Summary Возвращает историю объединений клиента с дублями
+// @Tags Clients
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Success 200 {object} GetClientMergesResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/merges [get]
+func (h *Handler) getClientMergesHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	merges, err := h.service.GetClientMerges(r.Context(), photographerID, clientID)
+	if err != nil {
+		log.Printf("get client merges error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if merges == nil {
+		merges = []domain.ClientMerge{}
+	}
+
+	encodeResponse(w, GetClientMergesResponse{Items: merges})
+}
@@ -0,0 +1,236 @@
+package http_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+	"time"
+)
+
+// @Summary Добавляет пакет услуг в прайс-лист
+// @Tags Packages
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param request body PackageRequest true "Пакет услуг с опциями"
+// @Success 200 {object} CreatePackageResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 409 {object} ProblemDetails "Пакет с таким названием уже есть"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/packages [post]
+func (h *Handler) createPackageHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req PackageRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pkg := req.pkg()
+	pkg.PhotographerID = photographerID
+
+	id, err := h.service.CreatePackage(r.Context(), pkg)
+	if err != nil {
+		log.Printf("create package error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, CreatePackageResponse{ID: id})
+}
+
+// @Summary Заменяет пакет услуг вместе с опциями
+// @Description Опции получают новые ID. Начисления, уже сделанные по пакету, не меняются.
+// @Tags Packages
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param pkgid path int true "ID пакета"
+// @Param request body PackageRequest true "Пакет услуг с опциями"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Пакет не найден или в архиве"
+// @Failure 409 {object} ProblemDetails "Пакет с таким названием уже есть"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/packages/{pkgid} [put]
+func (h *Handler) updatePackageHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, packageID, err := packageParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req PackageRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pkg := req.pkg()
+	pkg.ID = packageID
+	pkg.PhotographerID = photographerID
+
+	if err = h.service.UpdatePackage(r.Context(), pkg); err != nil {
+		log.Printf("update package error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Убирает пакет услуг в архив
+// @Description Пакет пропадает из прайс-листа, но остаётся у сделанных по нему начислений.
+// @Tags Packages
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param pkgid path int true "ID пакета"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Пакет не найден или уже в архиве"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/packages/{pkgid} [delete]
+func (h *Handler) archivePackageHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, packageID, err := packageParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err = h.service.ArchivePackage(r.Context(), photographerID, packageID); err != nil {
+		log.Printf("archive package error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Возвращает пакет услуг с опциями
+// @Tags Packages
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param pkgid path int true "ID пакета"
+// @Success 200 {object} domain.Package
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Пакет не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/packages/{pkgid} [get]
+func (h *Handler) getPackageHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, packageID, err := packageParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	pkg, err := h.service.GetPackage(r.Context(), photographerID, packageID)
+	if err != nil {
+		log.Printf("get package error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, pkg)
+}
+
+// @Summary Возвращает прайс-лист фотографа
+// @Tags Packages
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param include_archived query bool false "Показывать пакеты из архива"
+// @Success 200 {object} GetPackagesResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/packages [get]
+func (h *Handler) getPackagesHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	includeArchived, err := boolQuery(r, "include_archived")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	packages, err := h.service.GetPackages(r.Context(), photographerID, includeArchived)
+	if err != nil {
+		log.Printf("get packages error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if packages == nil {
+		packages = []domain.Package{}
+	}
+
+	encodeResponse(w, GetPackagesResponse{Items: packages})
+}
+
+// chargePackage начисляет клиенту пакет из прайс-листа по запросу на начисление.
+// Сумма берётся из прайс-листа, поэтому передавать её нельзя.
+func (h *Handler) chargePackage(r *http.Request, req AddDebtRequest, photographerID domain.PhotographerID,
+	clientID domain.ClientID, dueDate *time.Time) (domain.DebtID, domain.Balance, error) {
+	if req.Amount != 0 || req.Currency != "" {
+		var v domain.ValidationError
+		v.Add("amount", "must be omitted when package_id is set, the price list defines it")
+		return 0, domain.Balance{}, v.Err()
+	}
+
+	charge := domain.PackageCharge{
+		PhotographerID: photographerID,
+		ClientID:       clientID,
+		PackageID:      domain.PackageID(req.PackageID),
+		Quantity:       req.Quantity,
+		Description:    req.Description,
+		DueDate:        dueDate,
+	}
+	for _, addon := range req.Addons {
+		charge.Addons = append(charge.Addons, domain.AddonQuantity{ID: domain.AddonID(addon.ID), Quantity: addon.Quantity})
+	}
+
+	return h.service.ChargePackage(r.Context(), charge)
+}
+
+// pkg переводит тело запроса в пакет без фотографа.
+func (req PackageRequest) pkg() domain.Package {
+	pkg := domain.Package{
+		Name:            req.Name,
+		Description:     req.Description,
+		DurationMinutes: req.DurationMinutes,
+		Price:           domain.NewMoney(req.Price, req.Currency),
+		Deliverables:    req.Deliverables,
+	}
+	for _, addon := range req.Addons {
+		pkg.Addons = append(pkg.Addons, domain.Addon{
+			ID:    domain.AddonID(addon.ID),
+			Name:  addon.Name,
+			Price: domain.NewMoney(addon.Price, addon.Currency),
+		})
+	}
+
+	return pkg
+}
@@ -0,0 +1,267 @@
+package http_handler
+
+import (
+	"fmt"
+	"net/http"
+	"photographer/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Имена параметров пути. В v1 одни и те же сегменты назывались по-разному,
+// поэтому для каждого параметра перечислены все варианты.
+var (
+	photographerIDVars = []string{"pid", "photographerID"}
+	clientIDVars       = []string{"cid", "id"}
+	invoiceIDVars      = []string{"iid"}
+	paymentIDVars      = []string{"payid"}
+	sessionIDVars      = []string{"sid"}
+	packageIDVars      = []string{"pkgid"}
+	clientFieldIDVars  = []string{"fid"}
+)
+
+// badRequestError — ошибка разбора параметров запроса, отдаётся как 400.
+type badRequestError struct {
+	message string
+}
+
+func (e *badRequestError) Error() string {
+	return e.message
+}
+
+// photographerParam возвращает ID фотографа из пути или тела запроса и проверяет,
+// что он совпадает с аутентифицированным. Если ID не передан, берётся из токена.
+func photographerParam(r *http.Request, fromBody domain.PhotographerID) (domain.PhotographerID, error) {
+	id, ok, err := pathID(r, photographerIDVars)
+	if err != nil {
+		return 0, err
+	}
+
+	photographerID := fromBody
+	if ok {
+		photographerID = domain.PhotographerID(id)
+	}
+
+	if err = checkPhotographer(r, photographerID); err != nil {
+		return 0, err
+	}
+
+	return principal(r), nil
+}
+
+// clientParams возвращает фотографа и клиента, к которому обращается запрос.
+// ID клиента из пути важнее переданного в теле.
+func clientParams(r *http.Request, fromBody domain.ClientID) (domain.PhotographerID, domain.ClientID, error) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, ok, err := pathID(r, clientIDVars)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if ok {
+		return photographerID, domain.ClientID(id), nil
+	}
+
+	return photographerID, fromBody, nil
+}
+
+// invoiceParams возвращает фотографа и счёт из пути.
+func invoiceParams(r *http.Request) (domain.PhotographerID, domain.InvoiceID, error) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, _, err := pathID(r, invoiceIDVars)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return photographerID, domain.InvoiceID(id), nil
+}
+
+// paymentParams возвращает фотографа и оплату из пути.
+func paymentParams(r *http.Request) (domain.PhotographerID, domain.PaymentID, error) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, _, err := pathID(r, paymentIDVars)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return photographerID, domain.PaymentID(id), nil
+}
+
+// sessionParams возвращает фотографа и съёмку из пути.
+func sessionParams(r *http.Request) (domain.PhotographerID, domain.SessionID, error) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, _, err := pathID(r, sessionIDVars)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return photographerID, domain.SessionID(id), nil
+}
+
+// packageParams возвращает фотографа и пакет услуг из пути.
+func packageParams(r *http.Request) (domain.PhotographerID, domain.PackageID, error) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, _, err := pathID(r, packageIDVars)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return photographerID, domain.PackageID(id), nil
+}
+
+// clientFieldParams возвращает фотографа и поле карточки клиента из пути.
+func clientFieldParams(r *http.Request) (domain.PhotographerID, domain.ClientFieldID, error) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, _, err := pathID(r, clientFieldIDVars)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return photographerID, domain.ClientFieldID(id), nil
+}
+
+func pathID(r *http.Request, names []string) (int64, bool, error) {
+	vars := mux.Vars(r)
+	for _, name := range names {
+		raw, ok := vars[name]
+		if !ok {
+			continue
+		}
+
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return 0, false, &badRequestError{message: fmt.Sprintf("invalid %s '%s': must be an integer", name, raw)}
+		}
+
+		return id, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// listParams читает параметры постраничной выдачи из строки запроса. Даты from и
+// to включительные, в формате YYYY-MM-DD.
+func listParams(r *http.Request) (domain.ListParams, error) {
+	query := r.URL.Query()
+	params := domain.ListParams{Sort: query.Get("sort")}
+
+	var err error
+	if params.Limit, err = intQuery[int](r, "limit"); err != nil {
+		return domain.ListParams{}, err
+	}
+	if params.ClientID, err = intQuery[domain.ClientID](r, "client_id"); err != nil {
+		return domain.ListParams{}, err
+	}
+	if params.MinAmount, err = intQuery[int64](r, "min_amount"); err != nil {
+		return domain.ListParams{}, err
+	}
+	if params.From, err = dateQuery(r, "from"); err != nil {
+		return domain.ListParams{}, err
+	}
+	if params.To, err = dateQuery(r, "to"); err != nil {
+		return domain.ListParams{}, err
+	}
+	if params.To != nil {
+		to := params.To.AddDate(0, 0, 1)
+		params.To = &to
+	}
+
+	if params.IncludeDeleted, err = boolQuery(r, "include_deleted"); err != nil {
+		return domain.ListParams{}, err
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		if params.Cursor, err = domain.DecodeCursor(raw); err != nil {
+			return domain.ListParams{}, &badRequestError{message: "invalid cursor"}
+		}
+	}
+
+	return params, nil
+}
+
+func intQuery[T ~int | ~int64](r *http.Request, name string) (T, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, &badRequestError{message: fmt.Sprintf("invalid %s '%s': must be an integer", name, raw)}
+	}
+
+	return T(value), nil
+}
+
+func boolQuery(r *http.Request, name string) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, &badRequestError{message: fmt.Sprintf("invalid %s '%s': must be a boolean", name, raw)}
+	}
+
+	return value, nil
+}
+
+func dateQuery(r *http.Request, name string) (*time.Time, error) {
+	return parseDate(name, r.URL.Query().Get(name))
+}
+
+// parseDate разбирает необязательную дату в формате YYYY-MM-DD.
+func parseDate(name, raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, &badRequestError{message: fmt.Sprintf("invalid %s '%s': must be a date YYYY-MM-DD", name, raw)}
+	}
+
+	return &date, nil
+}
+
+// parseTimestamp разбирает необязательный момент времени в RFC 3339, пустая
+// строка даёт нулевое время.
+func parseTimestamp(name, raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, &badRequestError{message: fmt.Sprintf("invalid %s '%s': must be an RFC 3339 timestamp", name, raw)}
+	}
+
+	return t, nil
+}
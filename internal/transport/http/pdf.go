@@ -0,0 +1,145 @@
+package http_handler
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	pdfFont       = "Go"
+	pdfLineHeight = 6.0
+)
+
+// Ширины колонок таблицы позиций счёта в мм, вместе — ширина A4 без полей.
+var invoiceColumnWidths = []float64{10, 90, 20, 30, 30}
+
+// invoicePDF рисует счёт на A4. Шрифты Go встроены в бинарник и покрывают
+// кириллицу, поэтому PDF строится без внешних файлов и сети.
+func invoicePDF(photographer domain.Photographer, invoice domain.Invoice) *fpdf.Fpdf {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFont, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", gobold.TTF)
+	pdf.SetCreator(photographer.Name, true)
+	pdf.AddPage()
+
+	title := "Черновик счёта"
+	if invoice.Number != "" {
+		title = "Счёт № " + invoice.Number
+	}
+	pdf.SetTitle(title, true)
+
+	pdf.SetFont(pdfFont, "B", 18)
+	pdf.CellFormat(0, 10, title, "", 1, "L", false, 0, "")
+	if invoice.Status == domain.InvoiceVoid {
+		pdf.SetTextColor(200, 0, 0)
+		pdf.CellFormat(0, 8, "АННУЛИРОВАН", "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	pdf.SetFont(pdfFont, "", 11)
+	if invoice.IssueDate != nil {
+		pdf.CellFormat(0, pdfLineHeight, "Дата: "+invoice.IssueDate.Format("02.01.2006"), "", 1, "L", false, 0, "")
+	}
+	if invoice.DueDate != nil {
+		pdf.CellFormat(0, pdfLineHeight, "Оплатить до: "+invoice.DueDate.Format("02.01.2006"), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+	for _, line := range contractorLines(photographer) {
+		pdf.CellFormat(0, pdfLineHeight, line, "", 1, "L", false, 0, "")
+	}
+	pdf.CellFormat(0, pdfLineHeight, "Заказчик: "+invoice.ClientName, "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont(pdfFont, "B", 10)
+	pdf.SetFillColor(235, 235, 235)
+	for i, header := range []string{"№", "Наименование", "Кол-во", "Цена", "Сумма"} {
+		pdf.CellFormat(invoiceColumnWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont(pdfFont, "", 10)
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+	for i, item := range invoice.Items {
+		lines := pdf.SplitText(item.Description, invoiceColumnWidths[1]-2)
+		height := pdfLineHeight * float64(len(lines))
+		if pdf.GetY()+height > pageHeight-bottomMargin {
+			pdf.AddPage()
+		}
+
+		x, y := pdf.GetXY()
+		pdf.CellFormat(invoiceColumnWidths[0], height, strconv.Itoa(i+1), "1", 0, "C", false, 0, "")
+		pdf.MultiCell(invoiceColumnWidths[1], pdfLineHeight, item.Description, "1", "L", false)
+		pdf.SetXY(x+invoiceColumnWidths[0]+invoiceColumnWidths[1], y)
+		pdf.CellFormat(invoiceColumnWidths[2], height, strconv.Itoa(item.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(invoiceColumnWidths[3], height, item.UnitPrice.Decimal(), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(invoiceColumnWidths[4], height, item.Amount.Decimal(), "1", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.Ln(2)
+	pdf.CellFormat(0, 8, "Итого: "+invoice.Total.String(), "", 1, "R", false, 0, "")
+
+	if invoice.Note != "" {
+		pdf.Ln(4)
+		pdf.SetFont(pdfFont, "", 10)
+		pdf.MultiCell(0, 5, invoice.Note, "", "L", false)
+	}
+
+	return pdf
+}
+
+// contractorLines — блок «Исполнитель» счёта: юридическое имя, а без него имя для
+// клиентов или имя фотографа, затем ИНН и контакты, если они заполнены.
+func contractorLines(photographer domain.Photographer) []string {
+	name := photographer.LegalName
+	if name == "" {
+		name = photographer.DisplayName
+	}
+	if name == "" {
+		name = photographer.Name
+	}
+
+	lines := []string{"Исполнитель: " + name}
+	if photographer.TaxID != "" {
+		lines = append(lines, "ИНН: "+photographer.TaxID)
+	}
+
+	var contacts []string
+	for _, contact := range []string{photographer.Phone, photographer.Email} {
+		if contact != "" {
+			contacts = append(contacts, contact)
+		}
+	}
+	if len(contacts) > 0 {
+		lines = append(lines, "Контакты: "+strings.Join(contacts, ", "))
+	}
+
+	return lines
+}
+
+// encodePDF отдаёт PDF вложением. Ошибка отрисовки возвращается как problem+json,
+// пока в ответ ещё ничего не записано.
+func encodePDF(w http.ResponseWriter, r *http.Request, filename string, pdf *fpdf.Fpdf) {
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		log.Printf("pdf render error: %v", err)
+		writeError(w, r, fmt.Errorf("failed to render pdf: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("pdf write error: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package http_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+)
+
+// @Summary Возвращает профиль фотографа
+// @Tags Photographers
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Success 200 {object} domain.Photographer
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Фотограф не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid} [get]
+func (h *Handler) getPhotographerHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	photographer, err := h.service.GetPhotographer(r.Context(), photographerID)
+	if err != nil {
+		log.Printf("get photographer error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, photographer)
+}
+
+// @Summary Обновляет профиль фотографа
+// @Description Меняются только переданные поля. Валюту можно сменить, пока у фотографа нет ни одной суммы:
+// @Description начислений, оплат, счетов, съёмок и пакетов.
+// @Tags Photographers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param request body UpdatePhotographerRequest true "Изменяемые поля профиля"
+// @Success 200 {object} domain.Photographer
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа или фотограф деактивирован"
+// @Failure 404 {object} ProblemDetails "Фотограф не найден"
+// @Failure 409 {object} ProblemDetails "Валюту нельзя сменить: уже есть суммы"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid} [patch]
+func (h *Handler) updatePhotographerHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req UpdatePhotographerRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	photographer, err := h.service.UpdatePhotographer(r.Context(), photographerID, domain.PhotographerPatch{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		LegalName:   req.LegalName,
+		TaxID:       req.TaxID,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		TimeZone:    req.TimeZone,
+		Currency:    req.Currency,
+	})
+	if err != nil {
+		log.Printf("update photographer error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, photographer)
+}
+
+// @Summary Деактивирует фотографа
+// @Description Для фотографа, который уходит из студии. Клиенты, суммы и отчёты сохраняются и доступны
+// @Description на чтение, но любые изменения отклоняются с 403, а войти заново фотограф не может.
+// @Tags Photographers
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа или фотограф деактивирован"
+// @Failure 404 {object} ProblemDetails "Фотограф не найден"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/deactivate [post]
+func (h *Handler) deactivatePhotographerHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err = h.service.DeactivatePhotographer(r.Context(), photographerID); err != nil {
+		log.Printf("deactivate photographer error: %v", err)
+		writeError(w, r, err)
+	}
+}
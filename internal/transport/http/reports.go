@@ -0,0 +1,195 @@
+package http_handler
+
+import (
+	"bytes"
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+	"strconv"
+	"time"
+)
+
+// @Summary Возвращает задолженность клиентов по давности начислений
+// @Description Оплаты гасят начисления клиента начиная с самого старого, остаток
+// @Description раскладывается по корзинам 0–30, 31–60, 61–90 и более 90 дней.
+// @Tags Reports
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Param pid path int true "ID фотографа"
+// @Param as_of query string false "Дата отчёта (YYYY-MM-DD), по умолчанию сегодня"
+// @Param include_deleted query bool false "Учитывать удалённых клиентов"
+// @Param format query string false "Формат ответа" Enums(json, csv) default(json)
+// @Success 200 {object} domain.AgingReport
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/debtors/aging [get]
+func (h *Handler) getAgingHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	asOf, err := dateQuery(r, "as_of")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	format, err := formatQuery(r, "json", "csv")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	report, err := h.service.GetAgingReport(r.Context(), photographerID, asOf, params.IncludeDeleted)
+	if err != nil {
+		log.Printf("get aging report error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if format == "json" {
+		encodeResponse(w, report)
+		return
+	}
+
+	encodeCSV(w, fmt.Sprintf("aging-%s.csv", report.AsOf), agingRecords(report))
+}
+
+// agingRecords строит CSV отчёта о давности: суммы в основных единицах валюты,
+// итоговая строка последней и без ID клиента.
+func agingRecords(report domain.AgingReport) [][]string {
+	records := [][]string{{"client_id", "client_name", "currency", "days_0_30", "days_31_60", "days_61_90", "days_over_90", "total"}}
+	for _, row := range append(report.Clients, report.Total) {
+		clientID := ""
+		if row.ClientID != 0 {
+			clientID = strconv.FormatInt(int64(row.ClientID), 10)
+		}
+		records = append(records, []string{clientID, row.ClientName, row.Total.Currency,
+			row.Days0To30.Decimal(), row.Days31To60.Decimal(), row.Days61To90.Decimal(),
+			row.Over90.Decimal(), row.Total.Decimal()})
+	}
+	return records
+}
+
+// formatQuery читает параметр format, первый из допустимых форматов — формат по умолчанию.
+func formatQuery(r *http.Request, formats ...string) (string, error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		return formats[0], nil
+	}
+
+	for _, f := range formats {
+		if f == format {
+			return format, nil
+		}
+	}
+
+	return "", &badRequestError{message: fmt.Sprintf("invalid format '%s': must be one of %v", format, formats)}
+}
+
+func encodeCSV(w http.ResponseWriter, filename string, records [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if err := csv.NewWriter(w).WriteAll(records); err != nil {
+		log.Printf("csv encode error: %v", err)
+	}
+}
+
+// @Summary Возвращает выписку по клиенту за период
+// @Description Входящий баланс, все начисления и оплаты за период по порядку с балансом
+// @Description после каждой операции и исходящий баланс. С format=html — версия для печати.
+// @Tags Reports
+// @Security BearerAuth
+// @Produce json
+// @Produce html
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD), по умолчанию сегодня"
+// @Param format query string false "Формат ответа" Enums(json, html) default(json)
+// @Success 200 {object} domain.Statement
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/statement [get]
+func (h *Handler) getStatementHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	format, err := formatQuery(r, "json", "html")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	statement, err := h.service.GetStatement(r.Context(), photographerID, clientID, params)
+	if err != nil {
+		log.Printf("get statement error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if format == "json" {
+		encodeResponse(w, statement)
+		return
+	}
+
+	encodeHTML(w, "statement.html", statement)
+}
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"balance": formatBalance,
+	// lastDay показывает последний день полуинтервала, который заканчивается в To
+	"lastDay": func(t time.Time) string { return t.Add(-time.Nanosecond).Format("02.01.2006") },
+}).ParseFS(templateFiles, "templates/*.html"))
+
+func formatBalance(b domain.Balance) string {
+	if b.Credit.IsPositive() {
+		return "кредит " + b.Credit.String()
+	}
+	return b.Debt.String()
+}
+
+func encodeHTML(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("html render error: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("html write error: %v", err)
+	}
+}
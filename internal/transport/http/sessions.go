@@ -0,0 +1,275 @@
+package http_handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"photographer/internal/domain"
+)
+
+// @Summary Бронирует съёмку клиенту
+// @Description Съёмка не может пересекаться по времени с другой неотменённой съёмкой
+// @Description фотографа. Подтверждённая при создании съёмка сразу начисляется клиенту.
+// @Tags Sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повторный запрос с тем же ключом вернёт первый ответ"
+// @Param request body SessionRequest true "Время, место и цена съёмки"
+// @Success 200 {object} CreateSessionResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиент не найден"
+// @Failure 409 {object} ProblemDetails "Время занято другой съёмкой"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/sessions [post]
+func (h *Handler) createSessionHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req SessionRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := req.session()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	session.PhotographerID = photographerID
+	session.ClientID = clientID
+	session.Status = req.Status
+
+	id, err := h.service.CreateSession(r.Context(), session)
+	if err != nil {
+		log.Printf("create session error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, CreateSessionResponse{ID: id})
+}
+
+// @Summary Меняет время, место, тип, цену и примечание съёмки
+// @Description Статус в теле запроса не учитывается. Проведённую и отменённую съёмку
+// @Description менять нельзя; у подтверждённой изменение цены проводится корректировкой начисления.
+// @Tags Sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param sid path int true "ID съёмки"
+// @Param request body SessionRequest true "Время, место и цена съёмки"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Съёмка не найдена"
+// @Failure 409 {object} ProblemDetails "Съёмка завершена или время занято"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/sessions/{sid} [put]
+func (h *Handler) updateSessionHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, sessionID, err := sessionParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req SessionRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := req.session()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	session.ID = sessionID
+	session.PhotographerID = photographerID
+
+	if err = h.service.UpdateSession(r.Context(), session); err != nil {
+		log.Printf("update session error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Удаляет ошибочно заведённую съёмку
+// @Description Подтверждённую или проведённую съёмку удалить нельзя, её можно только отменить.
+// @Tags Sessions
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param sid path int true "ID съёмки"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Съёмка не найдена"
+// @Failure 409 {object} ProblemDetails "Съёмка уже начислена клиенту"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/sessions/{sid} [delete]
+func (h *Handler) deleteSessionHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, sessionID, err := sessionParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err = h.service.DeleteSession(r.Context(), photographerID, sessionID); err != nil {
+		log.Printf("delete session error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// @Summary Возвращает съёмку
+// @Tags Sessions
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param sid path int true "ID съёмки"
+// @Success 200 {object} domain.Session
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Съёмка не найдена"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/sessions/{sid} [get]
+func (h *Handler) getSessionHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, sessionID, err := sessionParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	session, err := h.service.GetSession(r.Context(), photographerID, sessionID)
+	if err != nil {
+		log.Printf("get session error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, session)
+}
+
+// @Summary Возвращает съёмки фотографа
+// @Tags Sessions
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param status query string false "Статус съёмки" Enums(tentative, confirmed, completed, cancelled)
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Сортировка: amount, date; '-' в начале — по убыванию" default(date)
+// @Param from query string false "Начинаются не раньше даты (YYYY-MM-DD)"
+// @Param to query string false "Начинаются не позже даты (YYYY-MM-DD)"
+// @Param client_id query int false "Только съёмки клиента"
+// @Param min_amount query int false "Цена не меньше суммы в минимальных единицах валюты"
+// @Param include_deleted query bool false "Показывать съёмки удалённых клиентов"
+// @Success 200 {object} GetSessionsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/sessions [get]
+func (h *Handler) getSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sessions, err := h.service.GetSessions(r.Context(), photographerID, r.URL.Query().Get("status"), params)
+	if err != nil {
+		log.Printf("get sessions error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, GetSessionsResponse{Items: sessions.Items, NextCursor: sessions.NextCursor})
+}
+
+// @Summary Меняет статус съёмки
+// @Description Предварительную съёмку можно подтвердить (confirmed) или отменить (cancelled),
+// @Description подтверждённую — провести (completed) или отменить. Подтверждение начисляет
+// @Description цену съёмки клиенту, отмена подтверждённой съёмки сторнирует начисление.
+// @Tags Sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param sid path int true "ID съёмки"
+// @Param request body ChangeSessionStatusRequest true "Новый статус"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Съёмка не найдена"
+// @Failure 409 {object} ProblemDetails "Переход из текущего статуса невозможен"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/sessions/{sid}/status [post]
+func (h *Handler) changeSessionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, sessionID, err := sessionParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req ChangeSessionStatusRequest
+
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("decode request body error: %v", err)
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = h.service.ChangeSessionStatus(r.Context(), photographerID, sessionID, req.Status); err != nil {
+		log.Printf("change session status error: %v", err)
+		writeError(w, r, err)
+	}
+}
+
+// session переводит тело запроса в съёмку без фотографа, клиента и статуса.
+func (req SessionRequest) session() (domain.Session, error) {
+	startsAt, err := parseTimestamp("starts_at", req.StartsAt)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	endsAt, err := parseTimestamp("ends_at", req.EndsAt)
+	if err != nil {
+		return domain.Session{}, err
+	}
+
+	return domain.Session{
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+		Location: req.Location,
+		Type:     req.Type,
+		Price:    domain.NewMoney(req.Price, req.Currency),
+		Note:     req.Note,
+	}, nil
+}
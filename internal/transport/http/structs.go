@@ -3,41 +3,299 @@ package http_handler
 import "photographer/internal/domain"
 
 type (
+	LoginRequest struct {
+		Login    string `json:"login" example:"alice"`
+		Password string `json:"password" example:"s3cret-pass"`
+	}
+
+	// CreatePhotographerRequest.TimeZone и Currency необязательны, по умолчанию
+	// Europe/Moscow и RUB.
 	CreatePhotographerRequest struct {
-		Name string `json:"name" example:"Alice"`
+		Name     string `json:"name" example:"Alice"`
+		Login    string `json:"login" example:"alice"`
+		Password string `json:"password" example:"s3cret-pass"`
+		TimeZone string `json:"time_zone,omitempty" example:"Asia/Yekaterinburg"`
+		Currency string `json:"currency,omitempty" example:"RUB"`
 	}
 
 	CreatePhotographerResponse struct {
 		ID domain.PhotographerID `json:"id" example:"1"`
 	}
 
+	// UpdatePhotographerRequest — частичное обновление: меняются только
+	// переданные поля, пустая строка стирает реквизит или контакт.
+	UpdatePhotographerRequest struct {
+		Name        *string `json:"name,omitempty" example:"Alice"`
+		DisplayName *string `json:"display_name,omitempty" example:"Alice Photo"`
+		LegalName   *string `json:"legal_name,omitempty" example:"ИП Иванова Алиса Сергеевна"`
+		TaxID       *string `json:"tax_id,omitempty" example:"770123456789"`
+		Email       *string `json:"email,omitempty" example:"alice@example.com"`
+		Phone       *string `json:"phone,omitempty" example:"+7 900 123-45-67"`
+		TimeZone    *string `json:"time_zone,omitempty" example:"Asia/Yekaterinburg"`
+		Currency    *string `json:"currency,omitempty" example:"RUB"`
+	}
+
+	// CreateClientRequest.PhotographerID необязателен: фотограф берётся из токена.
+	// Birthday — дата YYYY-MM-DD, CustomFields — значения полей, заведённых
+	// фотографом, по ключу поля.
 	CreateClientRequest struct {
-		PhotographerID domain.PhotographerID `json:"photographer_id" example:"1"`
+		PhotographerID domain.PhotographerID `json:"photographer_id,omitempty" example:"1"`
 		Name           string                `json:"name" example:"Alice"`
+		Contacts       []domain.Contact      `json:"contacts,omitempty"`
+		Birthday       string                `json:"birthday,omitempty" example:"1990-05-01"`
+		Source         string                `json:"source,omitempty" example:"Instagram"`
+		Notes          string                `json:"notes,omitempty" example:"Предпочитает съёмку на закате"`
+		CustomFields   map[string]any        `json:"custom_fields,omitempty" swaggertype:"object"`
 	}
 
 	CreateClientResponse struct {
 		ID domain.ClientID `json:"id" example:"1"`
 	}
 
+	// UpdateClientRequest — частичное обновление: меняются только переданные
+	// поля. Contacts заменяет список контактов целиком, пустой Birthday стирает
+	// дату рождения, в CustomFields меняются только переданные ключи, а null
+	// стирает значение.
 	UpdateClientRequest struct {
-		Name string `json:"name" example:"Alice Updated"`
+		Name         *string           `json:"name,omitempty" example:"Alice Updated"`
+		Contacts     *[]domain.Contact `json:"contacts,omitempty"`
+		Birthday     *string           `json:"birthday,omitempty" example:"1990-05-01"`
+		Source       *string           `json:"source,omitempty" example:"Рекомендация"`
+		Notes        *string           `json:"notes,omitempty"`
+		CustomFields map[string]any    `json:"custom_fields,omitempty" swaggertype:"object"`
 	}
 
+	// AddDebtRequest.PhotographerID необязателен: фотограф берётся из токена.
+	// ClientID нужен только для v1, в v2 клиент задаётся в пути.
+	// Amount — в минимальных единицах валюты, Currency по умолчанию валюта фотографа.
 	AddDebtRequest struct {
-		PhotographerID int `json:"photographer_id" example:"1"`
-		ClientID       int `json:"client_id" example:"2"`
-		Amount         int `json:"amount" example:"500"`
+		PhotographerID int    `json:"photographer_id,omitempty" example:"1"`
+		ClientID       int    `json:"client_id,omitempty" example:"2"`
+		Amount         int64  `json:"amount" example:"150050"`
+		Currency       string `json:"currency,omitempty" example:"RUB"`
+		Description    string `json:"description" example:"Свадебная съёмка"`
+		DueDate        string `json:"due_date,omitempty" example:"2025-03-01"`
+
+		// PackageID начисляет пакет из прайс-листа: сумма считается по ценам
+		// пакета и опций, Amount и Currency не передаются, Description необязателен.
+		PackageID int64                `json:"package_id,omitempty" example:"3"`
+		Quantity  int                  `json:"quantity,omitempty" example:"1"`
+		Addons    []ChargeAddonRequest `json:"addons,omitempty"`
+	}
+
+	// ChargeAddonRequest.Quantity по умолчанию 1.
+	ChargeAddonRequest struct {
+		ID       int64 `json:"id" example:"7"`
+		Quantity int   `json:"quantity,omitempty" example:"2"`
+	}
+
+	AddDebtResponse struct {
+		ID      domain.DebtID  `json:"id" example:"1"`
+		Balance domain.Balance `json:"balance"`
 	}
 
+	// AddPaymentRequest.PhotographerID необязателен: фотограф берётся из токена.
+	// ClientID нужен только для v1, в v2 клиент задаётся в пути.
+	// Amount — в минимальных единицах валюты, Currency по умолчанию валюта фотографа.
+	// Method по умолчанию other, OccurredAt (RFC 3339) — по умолчанию момент запроса.
 	AddPaymentRequest struct {
-		PhotographerID int `json:"photographer_id" example:"1"`
-		ClientID       int `json:"client_id" example:"2"`
-		Amount         int `json:"amount" example:"500"`
+		PhotographerID int    `json:"photographer_id,omitempty" example:"1"`
+		ClientID       int    `json:"client_id,omitempty" example:"2"`
+		Amount         int64  `json:"amount" example:"50000"`
+		Currency       string `json:"currency,omitempty" example:"RUB"`
+		Method         string `json:"method,omitempty" enums:"cash,card,bank_transfer,sbp,other" example:"cash"`
+		Reference      string `json:"reference,omitempty" example:"SBP-8841"`
+		Note           string `json:"note,omitempty" example:"Получено на съёмке"`
+		OccurredAt     string `json:"occurred_at,omitempty" example:"2026-02-10T18:30:00+03:00"`
+	}
+
+	AddPaymentResponse struct {
+		Balance domain.Balance `json:"balance"`
+	}
+
+	VoidPaymentRequest struct {
+		Reason string `json:"reason" example:"Оплата внесена дважды"`
+	}
+
+	// RefundPaymentRequest.Amount — в минимальных единицах валюты, Currency по умолчанию валюта фотографа.
+	RefundPaymentRequest struct {
+		Amount   int64  `json:"amount" example:"50000"`
+		Currency string `json:"currency,omitempty" example:"RUB"`
+		Reason   string `json:"reason,omitempty" example:"Отказ от ретуши"`
+	}
+
+	RefundPaymentResponse struct {
+		ID      domain.PaymentID `json:"id" example:"12"`
+		Balance domain.Balance   `json:"balance"`
+	}
+
+	// Ответы списков: NextCursor передаётся в cursor для следующей страницы
+	// и пуст на последней.
+	GetPhotographersResponse struct {
+		Items      []domain.PhotographerSummary `json:"items"`
+		NextCursor string                       `json:"next_cursor,omitempty"`
+	}
+
+	GetClientsResponse struct {
+		Items      []domain.Client `json:"items"`
+		NextCursor string          `json:"next_cursor,omitempty"`
+	}
+
+	SearchClientsResponse struct {
+		Items []domain.ClientMatch `json:"items"`
+	}
+
+	GetDuplicateClientsResponse struct {
+		Items []domain.DuplicateCandidate `json:"items"`
+	}
+
+	// MergeClientsRequest.SourceID — клиент, которого поглощает клиент из пути.
+	MergeClientsRequest struct {
+		SourceID domain.ClientID `json:"source_id" example:"7"`
 	}
 
+	// MergeClientsResponse.Balance — баланс клиента после объединения.
+	MergeClientsResponse struct {
+		Merge   domain.ClientMerge `json:"merge"`
+		Balance domain.Balance     `json:"balance"`
+	}
+
+	GetClientMergesResponse struct {
+		Items []domain.ClientMerge `json:"items"`
+	}
+
+	GetDebtorsResponse struct {
+		Items      []domain.Debt `json:"items"`
+		NextCursor string        `json:"next_cursor,omitempty"`
+	}
+
+	// GetIncomesResponse.Total — сумма всех оплат, попавших под фильтры, а не только текущей страницы.
 	GetIncomesResponse struct {
-		Payments []domain.Payment `json:"payments"`
-		Total    int              `json:"total" example:"10000"`
+		Items      []domain.Payment `json:"items"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+		Total      domain.Money     `json:"total"`
+
+		// ByMethod — та же сумма с разбивкой по способам оплаты.
+		ByMethod []domain.MethodTotal `json:"by_method"`
+
+		// Report есть в ответе, только если запрошен group_by.
+		Report *domain.IncomeReport `json:"report,omitempty"`
+	}
+
+	// InvoiceRequest — содержимое черновика счёта, при обновлении заменяется целиком.
+	InvoiceRequest struct {
+		Items   []InvoiceItemRequest `json:"items"`
+		DueDate string               `json:"due_date,omitempty" example:"2026-03-01"`
+		Note    string               `json:"note,omitempty" example:"Оплата переводом по реквизитам"`
+	}
+
+	// InvoiceItemRequest.UnitPrice — в минимальных единицах валюты, Currency по умолчанию валюта фотографа.
+	// Позиция с DebtID выставляет уже проведённое начисление клиента: сумма, валюта и
+	// по умолчанию описание берутся из начисления, повторно при выставлении оно не начисляется.
+	InvoiceItemRequest struct {
+		DebtID      *domain.DebtID `json:"debt_id,omitempty" example:"12"`
+		Description string         `json:"description" example:"Свадебная съёмка, 8 часов"`
+		Quantity    int            `json:"quantity" example:"1"`
+		UnitPrice   int64          `json:"unit_price" example:"4000000"`
+		Currency    string         `json:"currency,omitempty" example:"RUB"`
+	}
+
+	CreateInvoiceResponse struct {
+		ID domain.InvoiceID `json:"id" example:"1"`
+	}
+
+	// ChangeInvoiceStatusRequest.IssueDate допустим только при выставлении (sent),
+	// по умолчанию сегодня.
+	ChangeInvoiceStatusRequest struct {
+		Status    string `json:"status" example:"sent"`
+		IssueDate string `json:"issue_date,omitempty" example:"2026-02-15"`
+	}
+
+	GetInvoicesResponse struct {
+		Items      []domain.Invoice `json:"items"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+	}
+
+	// SessionRequest — съёмка. Время — в RFC 3339, Price — в минимальных единицах
+	// валюты, Currency по умолчанию валюта фотографа. Status задаётся только при
+	// создании: tentative (по умолчанию) или confirmed.
+	SessionRequest struct {
+		StartsAt string `json:"starts_at" example:"2026-06-13T14:00:00+03:00"`
+		EndsAt   string `json:"ends_at" example:"2026-06-13T18:00:00+03:00"`
+		Location string `json:"location,omitempty" example:"Парк Горького"`
+		Type     string `json:"type,omitempty" example:"Свадебная"`
+		Status   string `json:"status,omitempty" enums:"tentative,confirmed" example:"tentative"`
+		Price    int64  `json:"price" example:"4000000"`
+		Currency string `json:"currency,omitempty" example:"RUB"`
+		Note     string `json:"note,omitempty" example:"Взять второй объектив"`
+	}
+
+	CreateSessionResponse struct {
+		ID domain.SessionID `json:"id" example:"1"`
+	}
+
+	ChangeSessionStatusRequest struct {
+		Status string `json:"status" enums:"confirmed,completed,cancelled" example:"confirmed"`
+	}
+
+	GetSessionsResponse struct {
+		Items      []domain.Session `json:"items"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+	}
+
+	// CalendarTokenResponse.URL — ссылка на ленту для подписки в календаре.
+	CalendarTokenResponse struct {
+		Token string `json:"token" example:"mN3s8Qv1xYc2ZbT7kLw0pR5uE9aH4dJ6fG1iK8oV2sM"`
+		URL   string `json:"url" example:"https://api.example.com/api/v2/calendar/mN3s8Qv1xYc2ZbT7kLw0pR5uE9aH4dJ6fG1iK8oV2sM.ics"`
+	}
+
+	ImportSessionsResponse struct {
+		IDs []domain.SessionID `json:"ids" example:"1,2,3"`
+	}
+
+	// PackageRequest — пакет услуг, при обновлении заменяется целиком вместе с
+	// опциями. Цены — в минимальных единицах валюты, Currency по умолчанию валюта фотографа.
+	// Опция с ID обновляется на месте, без ID — добавляется, а не переданные
+	// опции уходят в архив.
+	PackageRequest struct {
+		Name            string         `json:"name" example:"Стандарт"`
+		Description     string         `json:"description,omitempty" example:"Репортажная съёмка до 3 часов"`
+		DurationMinutes int            `json:"duration_minutes,omitempty" example:"180"`
+		Price           int64          `json:"price" example:"3000000"`
+		Currency        string         `json:"currency,omitempty" example:"RUB"`
+		Deliverables    []string       `json:"deliverables,omitempty" example:"150 фото в обработке,онлайн-галерея"`
+		Addons          []AddonRequest `json:"addons,omitempty"`
+	}
+
+	AddonRequest struct {
+		ID       int64  `json:"id,omitempty" example:"3"`
+		Name     string `json:"name" example:"Дополнительный час"`
+		Price    int64  `json:"price" example:"800000"`
+		Currency string `json:"currency,omitempty" example:"RUB"`
+	}
+
+	CreatePackageResponse struct {
+		ID domain.PackageID `json:"id" example:"1"`
+	}
+
+	GetPackagesResponse struct {
+		Items []domain.Package `json:"items"`
+	}
+
+	// ClientFieldRequest — поле карточки клиента. Key и Type задаются только при
+	// создании, Options нужны только для типа select.
+	ClientFieldRequest struct {
+		Key     string   `json:"key,omitempty" example:"shoot_style"`
+		Label   string   `json:"label" example:"Стиль съёмки"`
+		Type    string   `json:"type,omitempty" enums:"text,number,date,select" example:"select"`
+		Options []string `json:"options,omitempty" example:"репортаж,постановка"`
+	}
+
+	CreateClientFieldResponse struct {
+		ID domain.ClientFieldID `json:"id" example:"1"`
+	}
+
+	GetClientFieldsResponse struct {
+		Items []domain.ClientField `json:"items"`
 	}
 )
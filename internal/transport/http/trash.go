@@ -0,0 +1,78 @@
+package http_handler
+
+import (
+	"log"
+	"net/http"
+)
+
+// @Summary Возвращает корзину удалённых клиентов
+// @Description Удалённые клиенты, которых ещё можно восстановить. По истечении срока хранения клиенты
+// @Description удаляются окончательно или обезличиваются и из корзины пропадают.
+// @Tags Clients
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param limit query int false "Размер страницы, по умолчанию 50, не больше 200"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Сортировка: name, amount, date, deleted; '-' в начале — по убыванию" default(-deleted)
+// @Param from query string false "Удалены не раньше даты (YYYY-MM-DD)"
+// @Param to query string false "Удалены не позже даты (YYYY-MM-DD)"
+// @Param min_amount query int false "Задолженность не меньше суммы в минимальных единицах валюты"
+// @Success 200 {object} GetClientsResponse
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 422 {object} ProblemDetails "Ошибка валидации"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/trash [get]
+func (h *Handler) getTrashHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, err := photographerParam(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	params, err := listParams(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	clients, err := h.service.GetTrash(r.Context(), photographerID, params)
+	if err != nil {
+		log.Printf("get trash error: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	encodeResponse(w, GetClientsResponse{Items: clients.Items, NextCursor: clients.NextCursor})
+}
+
+// @Summary Восстанавливает клиента из корзины
+// @Description Клиент возвращается в список вместе с начислениями, оплатами, съёмками и счетами.
+// @Description Клиента, поглощённого при объединении, восстановить нельзя.
+// @Tags Clients
+// @Security BearerAuth
+// @Produce json
+// @Param pid path int true "ID фотографа"
+// @Param cid path int true "ID клиента"
+// @Success 200
+// @Failure 400 {object} ProblemDetails
+// @Failure 401 {object} ProblemDetails "Требуется аутентификация"
+// @Failure 403 {object} ProblemDetails "Нет доступа к данным другого фотографа"
+// @Failure 404 {object} ProblemDetails "Клиента нет в корзине"
+// @Failure 409 {object} ProblemDetails "Клиент объединён с другим"
+// @Failure 500 {object} ProblemDetails
+// @Router /photographers/{pid}/clients/{cid}/restore [post]
+func (h *Handler) restoreClientHandler(w http.ResponseWriter, r *http.Request) {
+	photographerID, clientID, err := clientParams(r, 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err = h.service.RestoreClient(r.Context(), photographerID, clientID); err != nil {
+		log.Printf("restore client error: %v", err)
+		writeError(w, r, err)
+	}
+}
@@ -0,0 +1,4 @@
+ALTER TABLE debts
+    DROP COLUMN session_id;
+
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,34 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id              SERIAL PRIMARY KEY,
+    photographer_id INTEGER     NOT NULL,
+    client_id       INTEGER     NOT NULL,
+    starts_at       TIMESTAMPTZ NOT NULL,
+    ends_at         TIMESTAMPTZ NOT NULL,
+    location        TEXT        NOT NULL DEFAULT '',
+    type            TEXT        NOT NULL DEFAULT '',
+    status          TEXT        NOT NULL DEFAULT 'tentative',
+    price           BIGINT      NOT NULL,
+    currency        CHAR(3)     NOT NULL,
+    note            TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_photographer_id FOREIGN KEY (photographer_id) REFERENCES photographers (id) ON DELETE CASCADE,
+    CONSTRAINT fk_client_id FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE,
+    CONSTRAINT check_session_status CHECK (status IN ('tentative', 'confirmed', 'completed', 'cancelled')),
+    CONSTRAINT check_session_period CHECK (ends_at > starts_at),
+    CONSTRAINT check_session_price CHECK (price >= 0),
+    -- у фотографа не может быть двух пересекающихся съёмок, отменённые не в счёт
+    CONSTRAINT exclude_session_overlap EXCLUDE USING gist (
+        photographer_id WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+    ) WHERE (status <> 'cancelled')
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_photographer_starts ON sessions (photographer_id, starts_at);
+
+ALTER TABLE debts
+    ADD COLUMN session_id INTEGER,
+    ADD CONSTRAINT fk_session_id FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE SET NULL;
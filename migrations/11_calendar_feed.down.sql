@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS unique_photographer_calendar_token;
+
+ALTER TABLE photographers
+    DROP COLUMN calendar_token_hash;
@@ -0,0 +1,5 @@
+-- в базе хранится только хэш токена ленты календаря, как и у токенов доступа
+ALTER TABLE photographers
+    ADD COLUMN calendar_token_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_photographer_calendar_token ON photographers (calendar_token_hash);
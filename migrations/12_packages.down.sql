@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS debt_lines;
+
+ALTER TABLE debts
+    DROP COLUMN package_id;
+
+DROP TABLE IF EXISTS package_addons;
+DROP TABLE IF EXISTS packages;
@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS packages
+(
+    id               SERIAL PRIMARY KEY,
+    photographer_id  INTEGER     NOT NULL,
+    name             TEXT        NOT NULL,
+    description      TEXT        NOT NULL DEFAULT '',
+    duration_minutes INTEGER     NOT NULL DEFAULT 0,
+    price            BIGINT      NOT NULL,
+    currency         CHAR(3)     NOT NULL,
+    deliverables     TEXT[]      NOT NULL DEFAULT '{}',
+    archived_at      TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_photographer_id FOREIGN KEY (photographer_id) REFERENCES photographers (id) ON DELETE CASCADE,
+    CONSTRAINT check_package_duration CHECK (duration_minutes >= 0),
+    CONSTRAINT check_package_price CHECK (price >= 0)
+);
+
+-- архивный пакет не мешает завести новый с тем же названием
+CREATE UNIQUE INDEX IF NOT EXISTS unique_package_name ON packages (photographer_id, lower(name)) WHERE archived_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS package_addons
+(
+    id          SERIAL PRIMARY KEY,
+    package_id  INTEGER NOT NULL,
+    position    INTEGER NOT NULL,
+    name        TEXT    NOT NULL,
+    price       BIGINT  NOT NULL,
+    -- убранная из пакета опция остаётся в архиве: на неё ссылаются строки начислений
+    archived_at TIMESTAMPTZ,
+    CONSTRAINT fk_package_id FOREIGN KEY (package_id) REFERENCES packages (id) ON DELETE CASCADE,
+    CONSTRAINT check_package_addon_price CHECK (price >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_package_addons_package ON package_addons (package_id, position) WHERE archived_at IS NULL;
+
+ALTER TABLE debts
+    ADD COLUMN package_id INTEGER,
+    ADD CONSTRAINT fk_package_id FOREIGN KEY (package_id) REFERENCES packages (id) ON DELETE SET NULL;
+
+-- начисление по пакету хранит снимок прайс-листа на момент начисления: пакет и
+-- выбранные опции с количеством и ценой. Изменение или архивирование пакета
+-- проведённых начислений не меняет
+CREATE TABLE IF NOT EXISTS debt_lines
+(
+    id         SERIAL PRIMARY KEY,
+    debt_id    INTEGER NOT NULL,
+    position   INTEGER NOT NULL,
+    addon_id   INTEGER,
+    name       TEXT    NOT NULL,
+    quantity   INTEGER NOT NULL,
+    unit_price BIGINT  NOT NULL,
+    CONSTRAINT fk_debt_id FOREIGN KEY (debt_id) REFERENCES debts (id) ON DELETE CASCADE,
+    CONSTRAINT fk_addon_id FOREIGN KEY (addon_id) REFERENCES package_addons (id) ON DELETE SET NULL,
+    CONSTRAINT check_debt_line_quantity CHECK (quantity > 0),
+    CONSTRAINT check_debt_line_price CHECK (unit_price >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_debt_lines_debt ON debt_lines (debt_id, position);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS client_fields;
+
+ALTER TABLE clients
+    DROP COLUMN contacts,
+    DROP COLUMN birthday,
+    DROP COLUMN source,
+    DROP COLUMN notes,
+    DROP COLUMN custom_fields;
@@ -0,0 +1,22 @@
+-- контакты и значения своих полей клиента меняются только целиком вместе с
+-- карточкой клиента, поэтому хранятся в самой карточке
+ALTER TABLE clients
+    ADD COLUMN contacts      JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN birthday      DATE,
+    ADD COLUMN source        TEXT  NOT NULL DEFAULT '',
+    ADD COLUMN notes         TEXT  NOT NULL DEFAULT '',
+    ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS client_fields
+(
+    id              SERIAL PRIMARY KEY,
+    photographer_id INTEGER     NOT NULL,
+    key             TEXT        NOT NULL,
+    label           TEXT        NOT NULL,
+    type            TEXT        NOT NULL,
+    options         TEXT[]      NOT NULL DEFAULT '{}',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_photographer_id FOREIGN KEY (photographer_id) REFERENCES photographers (id) ON DELETE CASCADE,
+    CONSTRAINT check_client_field_type CHECK (type IN ('text', 'number', 'date', 'select')),
+    CONSTRAINT unique_client_field_key UNIQUE (photographer_id, key)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_clients_photographer;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,5 @@
+-- поиск сравнивает запрос с каждым клиентом фотографа по триграммам, поэтому
+-- достаточно индекса по фотографу
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_clients_photographer ON clients (photographer_id);
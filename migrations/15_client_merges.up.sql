@@ -0,0 +1,21 @@
+-- история объединения дублей: поглощённый клиент остаётся удалённым, а его
+-- начисления, оплаты, съёмки и счета переходят к клиенту target_id
+CREATE TABLE IF NOT EXISTS client_merges
+(
+    id              SERIAL PRIMARY KEY,
+    photographer_id INTEGER     NOT NULL,
+    target_id       INTEGER     NOT NULL,
+    source_id       INTEGER     NOT NULL,
+    source_name     TEXT        NOT NULL,
+    debts           INTEGER     NOT NULL,
+    payments        INTEGER     NOT NULL,
+    sessions        INTEGER     NOT NULL,
+    invoices        INTEGER     NOT NULL,
+    merged_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_photographer_id FOREIGN KEY (photographer_id) REFERENCES photographers (id) ON DELETE CASCADE,
+    CONSTRAINT fk_target_id FOREIGN KEY (target_id) REFERENCES clients (id) ON DELETE CASCADE,
+    CONSTRAINT fk_source_id FOREIGN KEY (source_id) REFERENCES clients (id) ON DELETE CASCADE,
+    CONSTRAINT unique_client_merge_source UNIQUE (source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_merges_target ON client_merges (target_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_clients_trash;
+
+ALTER TABLE clients
+    DROP COLUMN anonymized_at;
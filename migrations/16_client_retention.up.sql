@@ -0,0 +1,6 @@
+ALTER TABLE clients
+    ADD COLUMN anonymized_at TIMESTAMPTZ;
+
+-- обезличенный клиент считается окончательно удалённым: его нет в корзине и
+-- его нельзя восстановить, но начисления и оплаты остаются в отчётах
+CREATE INDEX IF NOT EXISTS idx_clients_trash ON clients (deleted_at) WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE photographers
+    DROP COLUMN IF EXISTS display_name,
+    DROP COLUMN IF EXISTS legal_name,
+    DROP COLUMN IF EXISTS tax_id,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS deactivated_at;
@@ -0,0 +1,10 @@
+-- деактивированный фотограф остаётся в базе вместе со всей историей, но не
+-- может вносить изменения
+ALTER TABLE photographers
+    ADD COLUMN display_name   TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN legal_name     TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN tax_id         TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN email          TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN phone          TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN updated_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN deactivated_at TIMESTAMPTZ;
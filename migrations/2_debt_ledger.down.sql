@@ -0,0 +1,27 @@
+DROP INDEX IF EXISTS idx_payments_photographer_client;
+DROP INDEX IF EXISTS idx_debts_photographer_client;
+
+CREATE TEMPORARY TABLE debt_balances AS
+SELECT d.photographer_id,
+       d.client_id,
+       SUM(d.amount) - COALESCE((SELECT SUM(p.amount)
+                                 FROM payments p
+                                 WHERE p.photographer_id = d.photographer_id
+                                   AND p.client_id = d.client_id), 0) AS amount,
+       MAX(d.occurred_at)                                                AS occurred_at
+FROM debts d
+GROUP BY d.photographer_id, d.client_id;
+
+DELETE FROM debts;
+
+INSERT INTO debts (photographer_id, client_id, amount, occurred_at)
+SELECT photographer_id, client_id, amount, occurred_at
+FROM debt_balances
+WHERE amount > 0;
+
+DROP TABLE debt_balances;
+
+ALTER TABLE debts
+    DROP COLUMN due_date,
+    DROP COLUMN description,
+    ADD CONSTRAINT unique_photographer_client UNIQUE (photographer_id, client_id);
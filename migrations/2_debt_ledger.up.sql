@@ -0,0 +1,27 @@
+ALTER TABLE debts DROP CONSTRAINT IF EXISTS unique_photographer_client;
+
+ALTER TABLE debts
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN due_date    DATE;
+
+-- до перехода на журнал в debts хранился остаток долга за вычетом оплат,
+-- восстанавливаем полную сумму начислений, чтобы баланс считался как начисления минус оплаты
+UPDATE debts d
+SET amount = d.amount + p.total
+FROM (SELECT photographer_id, client_id, SUM(amount) AS total
+      FROM payments
+      GROUP BY photographer_id, client_id) p
+WHERE d.photographer_id = p.photographer_id
+  AND d.client_id = p.client_id;
+
+INSERT INTO debts (photographer_id, client_id, amount, occurred_at)
+SELECT p.photographer_id, p.client_id, SUM(p.amount), MIN(p.occurred_at)
+FROM payments p
+WHERE NOT EXISTS (SELECT 1
+                  FROM debts d
+                  WHERE d.photographer_id = p.photographer_id
+                    AND d.client_id = p.client_id)
+GROUP BY p.photographer_id, p.client_id;
+
+CREATE INDEX IF NOT EXISTS idx_debts_photographer_client ON debts (photographer_id, client_id);
+CREATE INDEX IF NOT EXISTS idx_payments_photographer_client ON payments (photographer_id, client_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    key          TEXT        NOT NULL,
+    scope        TEXT        NOT NULL,
+    request_hash TEXT        NOT NULL,
+    status_code  INTEGER,
+    content_type TEXT,
+    body         BYTEA,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (key, scope)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS auth_tokens;
+
+DROP INDEX IF EXISTS unique_photographer_login;
+
+ALTER TABLE photographers
+    DROP COLUMN password_hash,
+    DROP COLUMN login;
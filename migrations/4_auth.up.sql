@@ -0,0 +1,16 @@
+ALTER TABLE photographers
+    ADD COLUMN login         TEXT,
+    ADD COLUMN password_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_photographer_login ON photographers (lower(login));
+
+CREATE TABLE IF NOT EXISTS auth_tokens
+(
+    token_hash      TEXT PRIMARY KEY,
+    photographer_id INTEGER     NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at      TIMESTAMPTZ NOT NULL,
+    CONSTRAINT fk_photographer_id FOREIGN KEY (photographer_id) REFERENCES photographers (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_tokens_expires_at ON auth_tokens (expires_at);
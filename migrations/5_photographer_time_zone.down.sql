@@ -0,0 +1,5 @@
+ALTER TABLE clients
+    ALTER COLUMN deleted_at TYPE TIMESTAMP USING deleted_at::timestamp;
+
+ALTER TABLE photographers
+    DROP COLUMN time_zone;
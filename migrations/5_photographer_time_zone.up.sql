@@ -0,0 +1,6 @@
+ALTER TABLE photographers
+    ADD COLUMN time_zone TEXT NOT NULL DEFAULT 'Europe/Moscow';
+
+-- deleted_at был единственной меткой времени без зоны
+ALTER TABLE clients
+    ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at::timestamptz;
@@ -0,0 +1,11 @@
+ALTER TABLE payments
+    DROP COLUMN currency,
+    ALTER COLUMN amount TYPE INTEGER USING (amount / 100)::integer;
+
+ALTER TABLE debts
+    DROP COLUMN currency,
+    ALTER COLUMN amount TYPE INTEGER USING (amount / 100)::integer;
+
+ALTER TABLE photographers
+    DROP CONSTRAINT check_photographer_currency,
+    DROP COLUMN currency;
@@ -0,0 +1,18 @@
+ALTER TABLE photographers
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    ADD CONSTRAINT check_photographer_currency CHECK (currency ~ '^[A-Z]{3}$');
+
+-- суммы хранились в рублях в INTEGER, переводим в копейки в BIGINT
+ALTER TABLE debts
+    ALTER COLUMN amount TYPE BIGINT USING amount::bigint * 100,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE payments
+    ALTER COLUMN amount TYPE BIGINT USING amount::bigint * 100,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE debts
+    ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE payments
+    ALTER COLUMN currency DROP DEFAULT;
@@ -0,0 +1,15 @@
+ALTER TABLE debts
+    DROP COLUMN invoice_id;
+
+DROP TABLE IF EXISTS invoice_counters;
+DROP TABLE IF EXISTS invoice_items;
+DROP TABLE IF EXISTS invoices;
+
+DELETE FROM debts WHERE kind <> 'charge';
+
+DROP INDEX IF EXISTS unique_debt_void;
+DROP INDEX IF EXISTS idx_debts_reversal_of;
+
+ALTER TABLE debts
+    DROP COLUMN reversal_of,
+    DROP COLUMN kind;
@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS invoices
+(
+    id              SERIAL PRIMARY KEY,
+    photographer_id INTEGER     NOT NULL,
+    client_id       INTEGER     NOT NULL,
+    number          TEXT,
+    status          TEXT        NOT NULL DEFAULT 'draft',
+    issue_date      DATE,
+    due_date        DATE,
+    note            TEXT        NOT NULL DEFAULT '',
+    currency        CHAR(3)     NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_photographer_id FOREIGN KEY (photographer_id) REFERENCES photographers (id) ON DELETE CASCADE,
+    CONSTRAINT fk_client_id FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE,
+    -- оплаченность счёта считается по оплатам клиента и не хранится
+    CONSTRAINT check_invoice_status CHECK (status IN ('draft', 'sent', 'void')),
+    CONSTRAINT check_invoice_number CHECK ((status = 'draft') = (number IS NULL)),
+    CONSTRAINT unique_invoice_number UNIQUE (photographer_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_photographer_client ON invoices (photographer_id, client_id);
+
+-- проведённые начисления не удаляются и не меняются: сторно (void) и
+-- корректировка (adjustment) ссылаются на исходное начисление и проводятся
+-- отдельной строкой, поэтому баланс по-прежнему считается простой суммой по debts
+ALTER TABLE debts
+    ADD COLUMN kind        TEXT NOT NULL DEFAULT 'charge',
+    ADD COLUMN reversal_of INTEGER,
+    ADD CONSTRAINT fk_debt_reversal_of FOREIGN KEY (reversal_of) REFERENCES debts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT check_debt_kind CHECK (kind IN ('charge', 'adjustment', 'void')),
+    ADD CONSTRAINT check_debt_reversal CHECK ((kind = 'charge') = (reversal_of IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_debts_reversal_of ON debts (reversal_of);
+CREATE UNIQUE INDEX IF NOT EXISTS unique_debt_void ON debts (reversal_of) WHERE kind = 'void';
+
+-- позиция счёта ссылается на начисление: уже начисленное клиенту повторно не
+-- начисляется, остальные позиции начисляются при выставлении счёта
+CREATE TABLE IF NOT EXISTS invoice_items
+(
+    id          SERIAL PRIMARY KEY,
+    invoice_id  INTEGER NOT NULL,
+    position    INTEGER NOT NULL,
+    description TEXT    NOT NULL,
+    quantity    INTEGER NOT NULL,
+    unit_price  BIGINT  NOT NULL,
+    debt_id     INTEGER,
+    CONSTRAINT fk_invoice_id FOREIGN KEY (invoice_id) REFERENCES invoices (id) ON DELETE CASCADE,
+    CONSTRAINT fk_invoice_item_debt FOREIGN KEY (debt_id) REFERENCES debts (id) ON DELETE SET NULL,
+    CONSTRAINT check_invoice_item_quantity CHECK (quantity > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_items_invoice ON invoice_items (invoice_id, position);
+CREATE INDEX IF NOT EXISTS idx_invoice_items_debt ON invoice_items (debt_id);
+
+-- счётчик номеров счетов фотографа по годам: номер берётся под блокировкой строки
+-- в той же транзакции, что и выставление счёта, поэтому пропусков не бывает
+CREATE TABLE IF NOT EXISTS invoice_counters
+(
+    photographer_id INTEGER NOT NULL,
+    year            INTEGER NOT NULL,
+    last_number     INTEGER NOT NULL,
+    PRIMARY KEY (photographer_id, year),
+    CONSTRAINT fk_photographer_id FOREIGN KEY (photographer_id) REFERENCES photographers (id) ON DELETE CASCADE
+);
+
+ALTER TABLE debts
+    ADD COLUMN invoice_id INTEGER,
+    ADD CONSTRAINT fk_invoice_id FOREIGN KEY (invoice_id) REFERENCES invoices (id) ON DELETE SET NULL;
@@ -0,0 +1,9 @@
+DELETE FROM payments WHERE kind <> 'payment';
+
+DROP INDEX IF EXISTS unique_payment_void;
+
+ALTER TABLE payments
+    DROP COLUMN voided_at,
+    DROP COLUMN reason,
+    DROP COLUMN reversal_of,
+    DROP COLUMN kind;
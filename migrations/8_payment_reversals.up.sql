@@ -0,0 +1,15 @@
+-- ошибочная оплата аннулируется сторно (void) той же датой, возврат клиенту
+-- (refund) проводится отдельной операцией датой возврата. Обе операции ссылаются
+-- на исходное движение и идут с обратным знаком, поэтому баланс и доходы
+-- по-прежнему считаются простой суммой по payments
+ALTER TABLE payments
+    ADD COLUMN kind        TEXT NOT NULL DEFAULT 'payment',
+    ADD COLUMN reversal_of INTEGER,
+    ADD COLUMN reason      TEXT NOT NULL DEFAULT '',
+    ADD COLUMN voided_at   TIMESTAMPTZ,
+    ADD CONSTRAINT fk_reversal_of FOREIGN KEY (reversal_of) REFERENCES payments (id) ON DELETE CASCADE,
+    ADD CONSTRAINT check_payment_kind CHECK (kind IN ('payment', 'refund', 'void')),
+    ADD CONSTRAINT check_payment_reversal CHECK ((kind = 'payment') = (reversal_of IS NULL)),
+    ADD CONSTRAINT check_payment_sign CHECK (kind = 'void' OR (kind = 'payment') = (amount > 0));
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_payment_void ON payments (reversal_of) WHERE kind = 'void';
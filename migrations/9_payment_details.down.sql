@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_payments_photographer_occurred;
+
+ALTER TABLE payments
+    DROP COLUMN note,
+    DROP COLUMN reference,
+    DROP COLUMN method;
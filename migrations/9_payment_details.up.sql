@@ -0,0 +1,8 @@
+-- способ оплаты прежних оплат неизвестен
+ALTER TABLE payments
+    ADD COLUMN method    TEXT NOT NULL DEFAULT 'other',
+    ADD COLUMN reference TEXT NOT NULL DEFAULT '',
+    ADD COLUMN note      TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT check_payment_method CHECK (method IN ('cash', 'card', 'bank_transfer', 'sbp', 'other'));
+
+CREATE INDEX IF NOT EXISTS idx_payments_photographer_occurred ON payments (photographer_id, occurred_at);